@@ -3,6 +3,7 @@ package ast
 import (
 	"bytes"
 	"jotlango/internal/lexer"
+	"strconv"
 	"strings"
 )
 
@@ -11,6 +12,7 @@ type Token = lexer.Token
 type Node interface {
 	TokenLiteral() string
 	String() string
+	Pos() lexer.Position
 }
 
 type Expression interface {
@@ -34,6 +36,13 @@ func (p *Program) TokenLiteral() string {
 	return ""
 }
 
+func (p *Program) Pos() lexer.Position {
+	if len(p.Statements) > 0 {
+		return p.Statements[0].Pos()
+	}
+	return lexer.Position{}
+}
+
 func (p *Program) String() string {
 	var out bytes.Buffer
 
@@ -51,21 +60,35 @@ type Identifier struct {
 
 func (i *Identifier) expressionNode()      {}
 func (i *Identifier) TokenLiteral() string { return i.Token.Literal }
+func (i *Identifier) Pos() lexer.Position  { return i.Token.Pos }
 func (i *Identifier) String() string       { return i.Value }
 
 type ClassStatement struct {
 	Token Token
 	Name  *Identifier
+	Bases []Expression // superclasse e/ou interfaces após `:`, como Base ou web.Middleware
 	Body  *BlockStatement
+	Doc   string // comentários /// antes da declaração
 }
 
 func (cs *ClassStatement) statementNode()       {}
 func (cs *ClassStatement) TokenLiteral() string { return cs.Token.Literal }
+func (cs *ClassStatement) Pos() lexer.Position  { return cs.Token.Pos }
 func (cs *ClassStatement) String() string {
 	var out bytes.Buffer
 
 	out.WriteString("class ")
 	out.WriteString(cs.Name.String())
+
+	if len(cs.Bases) > 0 {
+		bases := []string{}
+		for _, b := range cs.Bases {
+			bases = append(bases, b.String())
+		}
+		out.WriteString(" : ")
+		out.WriteString(strings.Join(bases, ", "))
+	}
+
 	out.WriteString(" {\n")
 	out.WriteString(cs.Body.String())
 	out.WriteString("\n}")
@@ -81,6 +104,7 @@ type CallStatement struct {
 
 func (cs *CallStatement) statementNode()       {}
 func (cs *CallStatement) TokenLiteral() string { return cs.Token.Literal }
+func (cs *CallStatement) Pos() lexer.Position  { return cs.Token.Pos }
 func (cs *CallStatement) String() string {
 	var out bytes.Buffer
 
@@ -102,11 +126,14 @@ type FunctionStatement struct {
 	Token      Token
 	Name       *Identifier
 	Parameters []*Identifier
+	ReturnType *Identifier
 	Body       *BlockStatement
+	Doc        string // comentários /// antes da declaração
 }
 
 func (fs *FunctionStatement) statementNode()       {}
 func (fs *FunctionStatement) TokenLiteral() string { return fs.Token.Literal }
+func (fs *FunctionStatement) Pos() lexer.Position  { return fs.Token.Pos }
 func (fs *FunctionStatement) String() string {
 	var out bytes.Buffer
 
@@ -135,6 +162,7 @@ type VarStatement struct {
 
 func (vs *VarStatement) statementNode()       {}
 func (vs *VarStatement) TokenLiteral() string { return vs.Token.Literal }
+func (vs *VarStatement) Pos() lexer.Position  { return vs.Token.Pos }
 func (vs *VarStatement) String() string {
 	var out bytes.Buffer
 
@@ -157,13 +185,14 @@ type ReturnStatement struct {
 
 func (rs *ReturnStatement) statementNode()       {}
 func (rs *ReturnStatement) TokenLiteral() string { return rs.Token.Literal }
+func (rs *ReturnStatement) Pos() lexer.Position  { return rs.Token.Pos }
 func (rs *ReturnStatement) String() string {
 	var out bytes.Buffer
 
-	out.WriteString("return ")
+	out.WriteString("return")
 
 	if rs.ReturnValue != nil {
-		out.WriteString(rs.ReturnValue.String())
+		out.WriteString(" " + rs.ReturnValue.String())
 	}
 
 	out.WriteString(";")
@@ -177,6 +206,7 @@ type ExpressionStatement struct {
 
 func (es *ExpressionStatement) statementNode()       {}
 func (es *ExpressionStatement) TokenLiteral() string { return es.Token.Literal }
+func (es *ExpressionStatement) Pos() lexer.Position  { return es.Token.Pos }
 func (es *ExpressionStatement) String() string {
 	if es.Expression != nil {
 		return es.Expression.String()
@@ -191,6 +221,7 @@ type BlockStatement struct {
 
 func (bs *BlockStatement) statementNode()       {}
 func (bs *BlockStatement) TokenLiteral() string { return bs.Token.Literal }
+func (bs *BlockStatement) Pos() lexer.Position  { return bs.Token.Pos }
 func (bs *BlockStatement) String() string {
 	var out bytes.Buffer
 
@@ -209,6 +240,7 @@ type CallExpression struct {
 
 func (ce *CallExpression) expressionNode()      {}
 func (ce *CallExpression) TokenLiteral() string { return ce.Token.Literal }
+func (ce *CallExpression) Pos() lexer.Position  { return ce.Token.Pos }
 func (ce *CallExpression) String() string {
 	var out bytes.Buffer
 
@@ -233,8 +265,36 @@ type StringLiteral struct {
 
 func (sl *StringLiteral) expressionNode()      {}
 func (sl *StringLiteral) TokenLiteral() string { return sl.Token.Literal }
+func (sl *StringLiteral) Pos() lexer.Position  { return sl.Token.Pos }
 func (sl *StringLiteral) String() string       { return sl.Token.Literal }
 
+// TemplateLiteral representa uma string com interpolação, "Olá, ${nome}!".
+// Parts tem os trechos de texto, como StringLiterals, e as expressões
+// interpoladas, na ordem em que aparecem
+type TemplateLiteral struct {
+	Token Token
+	Parts []Expression
+}
+
+func (tl *TemplateLiteral) expressionNode()      {}
+func (tl *TemplateLiteral) TokenLiteral() string { return tl.Token.Literal }
+func (tl *TemplateLiteral) Pos() lexer.Position  { return tl.Token.Pos }
+func (tl *TemplateLiteral) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("\"")
+	for _, part := range tl.Parts {
+		if text, ok := part.(*StringLiteral); ok {
+			out.WriteString(text.Value)
+		} else {
+			out.WriteString("${" + part.String() + "}")
+		}
+	}
+	out.WriteString("\"")
+
+	return out.String()
+}
+
 type NumberLiteral struct {
 	Token Token
 	Value float64
@@ -242,6 +302,7 @@ type NumberLiteral struct {
 
 func (nl *NumberLiteral) expressionNode()      {}
 func (nl *NumberLiteral) TokenLiteral() string { return nl.Token.Literal }
+func (nl *NumberLiteral) Pos() lexer.Position  { return nl.Token.Pos }
 func (nl *NumberLiteral) String() string       { return nl.Token.Literal }
 
 type Boolean struct {
@@ -251,8 +312,18 @@ type Boolean struct {
 
 func (b *Boolean) expressionNode()      {}
 func (b *Boolean) TokenLiteral() string { return b.Token.Literal }
+func (b *Boolean) Pos() lexer.Position  { return b.Token.Pos }
 func (b *Boolean) String() string       { return b.Token.Literal }
 
+type NullLiteral struct {
+	Token Token
+}
+
+func (n *NullLiteral) expressionNode()      {}
+func (n *NullLiteral) TokenLiteral() string { return n.Token.Literal }
+func (n *NullLiteral) Pos() lexer.Position  { return n.Token.Pos }
+func (n *NullLiteral) String() string       { return n.Token.Literal }
+
 type PrefixExpression struct {
 	Token    Token
 	Operator string
@@ -261,6 +332,7 @@ type PrefixExpression struct {
 
 func (pe *PrefixExpression) expressionNode()      {}
 func (pe *PrefixExpression) TokenLiteral() string { return pe.Token.Literal }
+func (pe *PrefixExpression) Pos() lexer.Position  { return pe.Token.Pos }
 func (pe *PrefixExpression) String() string {
 	var out bytes.Buffer
 
@@ -272,6 +344,20 @@ func (pe *PrefixExpression) String() string {
 	return out.String()
 }
 
+// PostfixExpression é um incremento ou decremento, x++ ou x--
+type PostfixExpression struct {
+	Token    Token
+	Operator string
+	Left     Expression
+}
+
+func (pe *PostfixExpression) expressionNode()      {}
+func (pe *PostfixExpression) TokenLiteral() string { return pe.Token.Literal }
+func (pe *PostfixExpression) Pos() lexer.Position  { return pe.Token.Pos }
+func (pe *PostfixExpression) String() string {
+	return "(" + pe.Left.String() + pe.Operator + ")"
+}
+
 type InfixExpression struct {
 	Token    Token
 	Left     Expression
@@ -281,6 +367,7 @@ type InfixExpression struct {
 
 func (ie *InfixExpression) expressionNode()      {}
 func (ie *InfixExpression) TokenLiteral() string { return ie.Token.Literal }
+func (ie *InfixExpression) Pos() lexer.Position  { return ie.Token.Pos }
 func (ie *InfixExpression) String() string {
 	var out bytes.Buffer
 
@@ -300,6 +387,7 @@ type ArrayLiteral struct {
 
 func (al *ArrayLiteral) expressionNode()      {}
 func (al *ArrayLiteral) TokenLiteral() string { return al.Token.Literal }
+func (al *ArrayLiteral) Pos() lexer.Position  { return al.Token.Pos }
 func (al *ArrayLiteral) String() string {
 	var out bytes.Buffer
 
@@ -318,16 +406,18 @@ func (al *ArrayLiteral) String() string {
 type HashLiteral struct {
 	Token Token
 	Pairs map[Expression]Expression
+	Keys  []Expression // chaves na ordem em que aparecem no código
 }
 
 func (hl *HashLiteral) expressionNode()      {}
 func (hl *HashLiteral) TokenLiteral() string { return hl.Token.Literal }
+func (hl *HashLiteral) Pos() lexer.Position  { return hl.Token.Pos }
 func (hl *HashLiteral) String() string {
 	var out bytes.Buffer
 
 	pairs := []string{}
-	for key, value := range hl.Pairs {
-		pairs = append(pairs, key.String()+":"+value.String())
+	for _, key := range hl.Keys {
+		pairs = append(pairs, key.String()+":"+hl.Pairs[key].String())
 	}
 
 	out.WriteString("{")
@@ -345,6 +435,7 @@ type IndexExpression struct {
 
 func (ie *IndexExpression) expressionNode()      {}
 func (ie *IndexExpression) TokenLiteral() string { return ie.Token.Literal }
+func (ie *IndexExpression) Pos() lexer.Position  { return ie.Token.Pos }
 func (ie *IndexExpression) String() string {
 	var out bytes.Buffer
 
@@ -356,3 +447,357 @@ func (ie *IndexExpression) String() string {
 
 	return out.String()
 }
+
+// SliceExpression representa left[start:end]. Start e End são nil quando
+// omitidos: arr[:2], arr[1:]
+type SliceExpression struct {
+	Token Token // o token [
+	Left  Expression
+	Start Expression
+	End   Expression
+}
+
+func (se *SliceExpression) expressionNode()      {}
+func (se *SliceExpression) TokenLiteral() string { return se.Token.Literal }
+func (se *SliceExpression) Pos() lexer.Position  { return se.Token.Pos }
+func (se *SliceExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(se.Left.String())
+	out.WriteString("[")
+	if se.Start != nil {
+		out.WriteString(se.Start.String())
+	}
+	out.WriteString(":")
+	if se.End != nil {
+		out.WriteString(se.End.String())
+	}
+	out.WriteString("])")
+
+	return out.String()
+}
+
+type IntegerLiteral struct {
+	Token Token
+	Value int64
+}
+
+func (il *IntegerLiteral) expressionNode()      {}
+func (il *IntegerLiteral) TokenLiteral() string { return il.Token.Literal }
+func (il *IntegerLiteral) Pos() lexer.Position  { return il.Token.Pos }
+func (il *IntegerLiteral) String() string       { return strconv.FormatInt(il.Value, 10) }
+
+type FloatLiteral struct {
+	Token Token
+	Value float64
+}
+
+func (fl *FloatLiteral) expressionNode()      {}
+func (fl *FloatLiteral) TokenLiteral() string { return fl.Token.Literal }
+func (fl *FloatLiteral) Pos() lexer.Position  { return fl.Token.Pos }
+func (fl *FloatLiteral) String() string       { return strconv.FormatFloat(fl.Value, 'f', -1, 64) }
+
+type IfExpression struct {
+	Token       Token
+	Condition   Expression
+	Consequence *BlockStatement
+	Alternative *BlockStatement
+}
+
+func (ie *IfExpression) expressionNode()      {}
+func (ie *IfExpression) TokenLiteral() string { return ie.Token.Literal }
+func (ie *IfExpression) Pos() lexer.Position  { return ie.Token.Pos }
+func (ie *IfExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("if")
+	out.WriteString(ie.Condition.String())
+	out.WriteString(" ")
+	out.WriteString(ie.Consequence.String())
+
+	if ie.Alternative != nil {
+		out.WriteString("else ")
+		out.WriteString(ie.Alternative.String())
+	}
+
+	return out.String()
+}
+
+type FunctionLiteral struct {
+	Token      Token
+	Parameters []*Identifier
+	Body       *BlockStatement
+}
+
+func (fl *FunctionLiteral) expressionNode()      {}
+func (fl *FunctionLiteral) TokenLiteral() string { return fl.Token.Literal }
+func (fl *FunctionLiteral) Pos() lexer.Position  { return fl.Token.Pos }
+func (fl *FunctionLiteral) String() string {
+	var out bytes.Buffer
+
+	params := []string{}
+	for _, p := range fl.Parameters {
+		params = append(params, p.String())
+	}
+
+	out.WriteString("fn(")
+	out.WriteString(strings.Join(params, ", "))
+	out.WriteString(") ")
+	out.WriteString(fl.Body.String())
+
+	return out.String()
+}
+
+type PropertyStatement struct {
+	Token Token
+	Name  *Identifier
+	Type  *Identifier
+	Value Expression // valor padrão, opcional
+	Doc   string     // comentários /// antes da declaração
+}
+
+func (ps *PropertyStatement) statementNode()       {}
+func (ps *PropertyStatement) TokenLiteral() string { return ps.Token.Literal }
+func (ps *PropertyStatement) Pos() lexer.Position  { return ps.Token.Pos }
+func (ps *PropertyStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("prop ")
+	out.WriteString(ps.Name.String())
+
+	if ps.Type != nil {
+		out.WriteString(": ")
+		out.WriteString(ps.Type.String())
+	}
+
+	if ps.Value != nil {
+		out.WriteString(" = ")
+		out.WriteString(ps.Value.String())
+	}
+
+	out.WriteString(";")
+	return out.String()
+}
+
+type PropertyExpression struct {
+	Token    Token
+	Object   Expression
+	Property *Identifier
+}
+
+func (pe *PropertyExpression) expressionNode()      {}
+func (pe *PropertyExpression) TokenLiteral() string { return pe.Token.Literal }
+func (pe *PropertyExpression) Pos() lexer.Position  { return pe.Token.Pos }
+func (pe *PropertyExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(pe.Object.String())
+	out.WriteString(".")
+	out.WriteString(pe.Property.String())
+
+	return out.String()
+}
+
+type NewExpression struct {
+	Token     Token
+	Class     Expression // Identifier ou PropertyExpression (http.Server)
+	Arguments []Expression
+}
+
+func (ne *NewExpression) expressionNode()      {}
+func (ne *NewExpression) TokenLiteral() string { return ne.Token.Literal }
+func (ne *NewExpression) Pos() lexer.Position  { return ne.Token.Pos }
+func (ne *NewExpression) String() string {
+	var out bytes.Buffer
+
+	args := []string{}
+	for _, a := range ne.Arguments {
+		args = append(args, a.String())
+	}
+
+	out.WriteString("new ")
+	out.WriteString(ne.Class.String())
+	out.WriteString("(")
+	out.WriteString(strings.Join(args, ", "))
+	out.WriteString(")")
+
+	return out.String()
+}
+
+// AssignmentExpression é uma atribuição simples, a = b, ou composta, como
+// a += b; Operator é o operador aplicado antes de atribuir, vazio em a = b
+type AssignmentExpression struct {
+	Token    Token
+	Operator string
+	Left     Expression
+	Value    Expression
+}
+
+func (ae *AssignmentExpression) expressionNode()      {}
+func (ae *AssignmentExpression) TokenLiteral() string { return ae.Token.Literal }
+func (ae *AssignmentExpression) Pos() lexer.Position  { return ae.Token.Pos }
+func (ae *AssignmentExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(ae.Left.String())
+	out.WriteString(" " + ae.Operator + "= ")
+	out.WriteString(ae.Value.String())
+
+	return out.String()
+}
+
+type WhileStatement struct {
+	Token     Token
+	Condition Expression
+	Body      *BlockStatement
+}
+
+func (ws *WhileStatement) statementNode()       {}
+func (ws *WhileStatement) TokenLiteral() string { return ws.Token.Literal }
+func (ws *WhileStatement) Pos() lexer.Position  { return ws.Token.Pos }
+func (ws *WhileStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("while ")
+	out.WriteString(ws.Condition.String())
+	out.WriteString(" {\n")
+	out.WriteString(ws.Body.String())
+	out.WriteString("\n}")
+
+	return out.String()
+}
+
+// ForStatement representa `for valor in iteravel` ou `for chave, valor in iteravel`.
+// Key é nil quando apenas uma variável é declarada
+type ForStatement struct {
+	Token    Token
+	Key      *Identifier
+	Value    *Identifier
+	Iterable Expression
+	Body     *BlockStatement
+}
+
+func (fs *ForStatement) statementNode()       {}
+func (fs *ForStatement) TokenLiteral() string { return fs.Token.Literal }
+func (fs *ForStatement) Pos() lexer.Position  { return fs.Token.Pos }
+func (fs *ForStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("for ")
+	if fs.Key != nil {
+		out.WriteString(fs.Key.String())
+		out.WriteString(", ")
+	}
+	out.WriteString(fs.Value.String())
+	out.WriteString(" in ")
+	out.WriteString(fs.Iterable.String())
+	out.WriteString(" {\n")
+	out.WriteString(fs.Body.String())
+	out.WriteString("\n}")
+
+	return out.String()
+}
+
+type BreakStatement struct {
+	Token Token
+}
+
+func (bs *BreakStatement) statementNode()       {}
+func (bs *BreakStatement) TokenLiteral() string { return bs.Token.Literal }
+func (bs *BreakStatement) Pos() lexer.Position  { return bs.Token.Pos }
+func (bs *BreakStatement) String() string       { return "break;" }
+
+type ContinueStatement struct {
+	Token Token
+}
+
+func (cs *ContinueStatement) statementNode()       {}
+func (cs *ContinueStatement) TokenLiteral() string { return cs.Token.Literal }
+func (cs *ContinueStatement) Pos() lexer.Position  { return cs.Token.Pos }
+func (cs *ContinueStatement) String() string       { return "continue;" }
+
+// MethodSignature é a declaração de um método sem corpo, usada em interfaces
+type MethodSignature struct {
+	Token      Token
+	Name       *Identifier
+	Parameters []*Identifier
+	ReturnType *Identifier
+	Doc        string // comentários /// antes da declaração
+}
+
+func (ms *MethodSignature) statementNode()       {}
+func (ms *MethodSignature) TokenLiteral() string { return ms.Token.Literal }
+func (ms *MethodSignature) Pos() lexer.Position  { return ms.Token.Pos }
+func (ms *MethodSignature) String() string {
+	var out bytes.Buffer
+
+	params := []string{}
+	for _, p := range ms.Parameters {
+		params = append(params, p.String())
+	}
+
+	out.WriteString("fn ")
+	out.WriteString(ms.Name.String())
+	out.WriteString("(")
+	out.WriteString(strings.Join(params, ", "))
+	out.WriteString(")")
+
+	if ms.ReturnType != nil {
+		out.WriteString(": ")
+		out.WriteString(ms.ReturnType.String())
+	}
+
+	return out.String()
+}
+
+type InterfaceStatement struct {
+	Token   Token
+	Name    *Identifier
+	Methods []*MethodSignature
+	Doc     string // comentários /// antes da declaração
+}
+
+func (is *InterfaceStatement) statementNode()       {}
+func (is *InterfaceStatement) TokenLiteral() string { return is.Token.Literal }
+func (is *InterfaceStatement) Pos() lexer.Position  { return is.Token.Pos }
+func (is *InterfaceStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("interface ")
+	out.WriteString(is.Name.String())
+	out.WriteString(" {\n")
+
+	for _, m := range is.Methods {
+		out.WriteString(m.String())
+		out.WriteString("\n")
+	}
+
+	out.WriteString("}")
+
+	return out.String()
+}
+
+type ImportStatement struct {
+	Token Token
+	Path  string
+	Alias *Identifier // nome local opcional definido com `as`
+}
+
+func (is *ImportStatement) statementNode()       {}
+func (is *ImportStatement) TokenLiteral() string { return is.Token.Literal }
+func (is *ImportStatement) Pos() lexer.Position  { return is.Token.Pos }
+func (is *ImportStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("import \"")
+	out.WriteString(is.Path)
+	out.WriteString("\"")
+
+	if is.Alias != nil {
+		out.WriteString(" as ")
+		out.WriteString(is.Alias.String())
+	}
+
+	return out.String()
+}
@@ -0,0 +1,145 @@
+package diag
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"jotlango/internal/lexer"
+)
+
+// Severity indica a gravidade de um diagnóstico
+type Severity int
+
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+	SeverityInfo
+)
+
+var severityNames = map[Severity]string{
+	SeverityError:   "error",
+	SeverityWarning: "warning",
+	SeverityInfo:    "info",
+}
+
+func (s Severity) String() string {
+	if name, ok := severityNames[s]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// MarshalJSON serializa a severidade pelo nome
+func (s Severity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// Códigos de diagnóstico emitidos pelo lexer (L), parser (P) e avaliador (R)
+const (
+	CodeIllegalChar     = "L001"
+	CodeMalformedToken  = "L002"
+	CodeUnexpectedToken = "P001"
+	CodeNoPrefixParse   = "P002"
+	CodeInvalidNumber   = "P003"
+	CodeUnclosedBlock   = "P004"
+	CodeOutsideLoop     = "P005"
+	CodeInvalidAssign   = "P006"
+	CodeRuntime         = "R001"
+)
+
+// Span representa um trecho do código-fonte
+type Span struct {
+	Start lexer.Position `json:"start"`
+	End   lexer.Position `json:"end"`
+}
+
+// TokenSpan retorna o trecho ocupado por um token. Colunas contam
+// caracteres, não bytes. Tokens criados fora do lexer, sem End, são
+// medidos pelo Literal
+func TokenSpan(tok lexer.Token) Span {
+	if tok.End.IsValid() {
+		return Span{Start: tok.Pos, End: tok.End}
+	}
+	end := tok.Pos
+	end.Column += utf8.RuneCountInString(tok.Literal)
+	return Span{Start: tok.Pos, End: end}
+}
+
+// PosSpan retorna um trecho vazio começando em pos
+func PosSpan(pos lexer.Position) Span {
+	return Span{Start: pos, End: pos}
+}
+
+// Note é uma informação relacionada a um diagnóstico em outro ponto do código
+type Note struct {
+	Message string `json:"message"`
+	Span    Span   `json:"span"`
+}
+
+// Fix é uma sugestão de correção que substitui o trecho Span por Replacement
+type Fix struct {
+	Message     string `json:"message"`
+	Span        Span   `json:"span"`
+	Replacement string `json:"replacement"`
+}
+
+// Diagnostic representa um erro, aviso ou informação sobre o código-fonte
+type Diagnostic struct {
+	Severity Severity `json:"severity"`
+	Code     string   `json:"code"`
+	Message  string   `json:"message"`
+	Span     Span     `json:"span"`
+	Related  []Note   `json:"related,omitempty"`
+	Fix      *Fix     `json:"fix,omitempty"`
+}
+
+// Errorf cria um diagnóstico de erro
+func Errorf(code string, span Span, format string, a ...interface{}) Diagnostic {
+	return Diagnostic{
+		Severity: SeverityError,
+		Code:     code,
+		Message:  fmt.Sprintf(format, a...),
+		Span:     span,
+	}
+}
+
+// String formata o diagnóstico como file:line:col: mensagem
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s", d.Span.Start, d.Message)
+}
+
+// HasErrors indica se algum dos diagnósticos é um erro
+func HasErrors(diags []Diagnostic) bool {
+	for _, d := range diags {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteText escreve os diagnósticos em formato legível, um por linha,
+// seguidos das notas relacionadas e da correção sugerida
+func WriteText(w io.Writer, diags []Diagnostic) {
+	for _, d := range diags {
+		fmt.Fprintf(w, "%s: %s[%s]: %s\n", d.Span.Start, d.Severity, d.Code, d.Message)
+		for _, note := range d.Related {
+			fmt.Fprintf(w, "  %s: note: %s\n", note.Span.Start, note.Message)
+		}
+		if d.Fix != nil {
+			fmt.Fprintf(w, "  %s: fix: %s\n", d.Fix.Span.Start, d.Fix.Message)
+		}
+	}
+}
+
+// WriteJSON escreve os diagnósticos como um array JSON
+func WriteJSON(w io.Writer, diags []Diagnostic) error {
+	if diags == nil {
+		diags = []Diagnostic{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(diags)
+}
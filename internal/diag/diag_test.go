@@ -0,0 +1,60 @@
+package diag
+
+import (
+	"testing"
+
+	"jotlango/internal/lexer"
+)
+
+func TestTokenSpan(t *testing.T) {
+	tests := []struct {
+		input string
+		end   int // coluna logo após o primeiro token
+	}{
+		{"nome = 1", 5},
+		{"função()", 7},
+		{`"abc" x`, 6},
+		{`"a\tb" x`, 7},
+		{`"\u{2713} ok" x`, 14},
+		{`"olá ${nome}" x`, 14},
+		{"`a\\nb` x", 7},
+		{">= 1", 3},
+	}
+
+	for _, tt := range tests {
+		tok := lexer.NewLexer(tt.input).NextToken()
+		span := TokenSpan(tok)
+		if span.Start.Line != 1 || span.Start.Column != 1 {
+			t.Errorf("%q: start = %d:%d, want 1:1", tt.input, span.Start.Line, span.Start.Column)
+		}
+		if span.End.Line != 1 || span.End.Column != tt.end {
+			t.Errorf("%q: end = %d:%d, want 1:%d", tt.input, span.End.Line, span.End.Column, tt.end)
+		}
+	}
+}
+
+func TestTokenSpanMultiline(t *testing.T) {
+	tok := lexer.NewLexer("\"\"\"\nlinha 1\nlinha 2\"\"\" x").NextToken()
+	if end := TokenSpan(tok).End; end.Line != 3 || end.Column != 11 {
+		t.Errorf("end = %d:%d, want 3:11", end.Line, end.Column)
+	}
+}
+
+func TestTokenSpanInvalidEscape(t *testing.T) {
+	// O erro cobre só a sequência de escape inválida
+	tok := lexer.NewLexer(`"ab\qcd"`).NextToken()
+	if tok.Type != lexer.TokenError {
+		t.Fatalf("got %s, want an error token", tok.Type)
+	}
+	span := TokenSpan(tok)
+	if span.Start.Column != 4 || span.End.Column != 6 {
+		t.Errorf("span = %d-%d, want 4-6", span.Start.Column, span.End.Column)
+	}
+}
+
+func TestTokenSpanWithoutEnd(t *testing.T) {
+	tok := lexer.Token{Type: lexer.TokenGT, Literal: ">", Pos: lexer.Position{Line: 2, Column: 5}}
+	if end := TokenSpan(tok).End; end.Line != 2 || end.Column != 6 {
+		t.Errorf("end = %d:%d, want 2:6", end.Line, end.Column)
+	}
+}
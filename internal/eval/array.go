@@ -0,0 +1,657 @@
+package eval
+
+import (
+	"sort"
+	"strings"
+
+	"jotlango/internal/object"
+)
+
+// strictIndex faz acessos fora dos limites de um array resultarem em erro
+// em vez de null, e fatias fora dos limites deixarem de ser ajustadas
+var strictIndex bool
+
+// SetStrictIndex liga ou desliga o modo estrito de índices
+func SetStrictIndex(strict bool) {
+	strictIndex = strict
+}
+
+// resolveIndex converte um índice, que pode ser negativo e contar a partir
+// do fim, em uma posição do array. ok é false se a posição não existe
+func resolveIndex(idx int64, length int) (int, bool) {
+	if idx < 0 {
+		idx += int64(length)
+	}
+	if idx < 0 || idx >= int64(length) {
+		return 0, false
+	}
+	return int(idx), true
+}
+
+func indexOutOfRange(idx int64, length int) *object.Error {
+	return newError("index out of range: %d (length %d)", idx, length)
+}
+
+func evalArrayIndexExpression(array, index object.Object) object.Object {
+	arrayObject := array.(*object.Array)
+	idx := index.(*object.Integer).Value
+
+	length := arrayObject.Len()
+	if i, ok := resolveIndex(idx, length); ok {
+		if element, ok := arrayObject.Get(i); ok {
+			return element
+		}
+	}
+	if strictIndex {
+		return indexOutOfRange(idx, length)
+	}
+	return NULL
+}
+
+// evalArrayIndexAssignment substitui um elemento existente. Atribuir fora
+// dos limites é sempre um erro, mesmo fora do modo estrito
+func evalArrayIndexAssignment(array *object.Array, index, value object.Object) object.Object {
+	n, ok := index.(*object.Integer)
+	if !ok {
+		return newError("array index must be INTEGER, got %s", index.Type())
+	}
+
+	length := array.Len()
+	i, ok := resolveIndex(n.Value, length)
+	if !ok || !array.Set(i, value) {
+		return indexOutOfRange(n.Value, length)
+	}
+	return value
+}
+
+// sliceBounds resolve os limites de uma fatia. Limites omitidos (nil) vão
+// do início ao fim e negativos contam a partir do fim. Fora do modo
+// estrito, limites além do array são ajustados como em Python
+func sliceBounds(start, end object.Object, length int) (int, int, *object.Error) {
+	bound := func(obj object.Object, fallback int64) (int64, *object.Error) {
+		if obj == nil {
+			return fallback, nil
+		}
+		n, ok := obj.(*object.Integer)
+		if !ok {
+			return 0, newError("slice bounds must be INTEGER, got %s", obj.Type())
+		}
+		if n.Value < 0 {
+			return n.Value + int64(length), nil
+		}
+		return n.Value, nil
+	}
+
+	lo, err := bound(start, 0)
+	if err != nil {
+		return 0, 0, err
+	}
+	hi, err := bound(end, int64(length))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if lo < 0 || hi > int64(length) || lo > hi {
+		if strictIndex {
+			return 0, 0, newError("slice bounds out of range: [%s:%s] (length %d)",
+				inspectBound(start), inspectBound(end), length)
+		}
+		lo = clamp(lo, 0, int64(length))
+		hi = clamp(hi, lo, int64(length))
+	}
+
+	return int(lo), int(hi), nil
+}
+
+func inspectBound(obj object.Object) string {
+	if obj == nil {
+		return ""
+	}
+	return obj.Inspect()
+}
+
+func clamp(n, lo, hi int64) int64 {
+	if n < lo {
+		return lo
+	}
+	if n > hi {
+		return hi
+	}
+	return n
+}
+
+// evalSliceExpression retorna um novo array com os elementos da fatia, ou
+// uma nova string com os caracteres da fatia
+func evalSliceExpression(left, start, end object.Object) object.Object {
+	switch left := left.(type) {
+	case *object.Array:
+		elements := left.Snapshot()
+		lo, hi, err := sliceBounds(start, end, len(elements))
+		if err != nil {
+			return err
+		}
+		return &object.Array{Elements: elements[lo:hi:hi]}
+	case *object.String:
+		runes := []rune(left.Value)
+		lo, hi, err := sliceBounds(start, end, len(runes))
+		if err != nil {
+			return err
+		}
+		return &object.String{Value: string(runes[lo:hi])}
+	default:
+		return newError("slice operator not supported: %s", left.Type())
+	}
+}
+
+// evalSliceAssignment troca os elementos da fatia pelos de value, que pode
+// ter outro tamanho: arr[1:3] = [] remove dois elementos
+func evalSliceAssignment(left, start, end, value object.Object) object.Object {
+	array, ok := left.(*object.Array)
+	if !ok {
+		return newError("slice assignment not supported: %s", left.Type())
+	}
+	replacement, ok := value.(*object.Array)
+	if !ok {
+		return newError("can only assign an ARRAY to a slice, got %s", value.Type())
+	}
+
+	// Copiado antes do Update, já que replacement pode ser o próprio array
+	inserted := replacement.Snapshot()
+
+	var err *object.Error
+	array.Update(func(current []object.Object) []object.Object {
+		var lo, hi int
+		lo, hi, err = sliceBounds(start, end, len(current))
+		if err != nil {
+			return current
+		}
+		elements := make([]object.Object, 0, len(current)-(hi-lo)+len(inserted))
+		elements = append(elements, current[:lo]...)
+		elements = append(elements, inserted...)
+		return append(elements, current[hi:]...)
+	})
+	if err != nil {
+		return err
+	}
+	return value
+}
+
+// Funções de ordem superior sobre arrays. Ficam fora do mapa builtins
+// porque chamam applyFunction, que por sua vez consulta builtins. Nenhuma
+// altera o array recebido: as que produzem arrays retornam um novo
+func init() {
+	for name, fn := range map[string]object.BuiltinFunction{
+		"map":      builtinMap,
+		"filter":   builtinFilter,
+		"reduce":   builtinReduce,
+		"each":     builtinEach,
+		"find":     builtinFind,
+		"any":      builtinAny,
+		"all":      builtinAll,
+		"sort":     builtinSort,
+		"reverse":  builtinReverse,
+		"join":     builtinJoin,
+		"contains": builtinContains,
+		"indexOf":  builtinIndexOf,
+		"flatten":  builtinFlatten,
+		"zip":      builtinZip,
+		"unique":   builtinUnique,
+		"range":    builtinRange,
+	} {
+		builtins[name] = &object.Builtin{Fn: fn}
+	}
+}
+
+// arrayArg valida o primeiro argumento e a quantidade de argumentos
+func arrayArg(name string, args []object.Object, min, max int) (*object.Array, *object.Error) {
+	if len(args) < min || len(args) > max {
+		if min == max {
+			return nil, newError("wrong number of arguments to `%s`. got=%d, want=%d", name, len(args), min)
+		}
+		return nil, newError("wrong number of arguments to `%s`. got=%d, want=%d to %d", name, len(args), min, max)
+	}
+	arr, ok := args[0].(*object.Array)
+	if !ok {
+		return nil, newError("argument to `%s` must be ARRAY, got %s", name, args[0].Type())
+	}
+	return arr, nil
+}
+
+// functionArg valida o argumento i, que deve ser chamável
+func functionArg(name string, args []object.Object, i int) *object.Error {
+	switch args[i].(type) {
+	case *object.Function, *object.Builtin, *BoundMethod:
+		return nil
+	}
+	return newError("argument %d to `%s` must be a function, got %s", i+1, name, args[i].Type())
+}
+
+// callback chama fn com o elemento e o índice, descartando o índice se a
+// função declarar um único parâmetro: map(a, fn(x) {...}) e
+// map(a, fn(x, i) {...}) funcionam. Builtins recebem só o elemento, então
+// map(a, len) também funciona
+func callback(fn object.Object, element object.Object, index int) object.Object {
+	args := []object.Object{element, &object.Integer{Value: int64(index)}}
+
+	params := -1
+	switch fn := fn.(type) {
+	case *object.Function:
+		params = len(fn.Parameters)
+	case *BoundMethod:
+		params = len(fn.Method.Parameters)
+	case *object.Builtin:
+		params = 1
+	}
+	if params >= 0 && params < len(args) {
+		args = args[:params]
+	}
+
+	return applyFunction(fn, args)
+}
+
+func builtinMap(args ...object.Object) object.Object {
+	arr, err := arrayArg("map", args, 2, 2)
+	if err != nil {
+		return err
+	}
+	if err := functionArg("map", args, 1); err != nil {
+		return err
+	}
+
+	elements := arr.Snapshot()
+	result := make([]object.Object, len(elements))
+	for i, element := range elements {
+		value := callback(args[1], element, i)
+		if isError(value) {
+			return value
+		}
+		result[i] = value
+	}
+	return &object.Array{Elements: result}
+}
+
+func builtinFilter(args ...object.Object) object.Object {
+	arr, err := arrayArg("filter", args, 2, 2)
+	if err != nil {
+		return err
+	}
+	if err := functionArg("filter", args, 1); err != nil {
+		return err
+	}
+
+	result := []object.Object{}
+	for i, element := range arr.Snapshot() {
+		keep := callback(args[1], element, i)
+		if isError(keep) {
+			return keep
+		}
+		if isTruthy(keep) {
+			result = append(result, element)
+		}
+	}
+	return &object.Array{Elements: result}
+}
+
+// builtinReduce chama fn(acumulador, elemento). Sem valor inicial, o
+// primeiro elemento é o acumulador
+func builtinReduce(args ...object.Object) object.Object {
+	arr, err := arrayArg("reduce", args, 2, 3)
+	if err != nil {
+		return err
+	}
+	if err := functionArg("reduce", args, 1); err != nil {
+		return err
+	}
+
+	elements := arr.Snapshot()
+	var acc object.Object
+	if len(args) == 3 {
+		acc = args[2]
+	} else {
+		if len(elements) == 0 {
+			return newError("reduce of empty array with no initial value")
+		}
+		acc = elements[0]
+		elements = elements[1:]
+	}
+
+	for _, element := range elements {
+		acc = applyFunction(args[1], []object.Object{acc, element})
+		if isError(acc) {
+			return acc
+		}
+	}
+	return acc
+}
+
+// builtinEach para quando o callback retorna false, como io.EachLine
+func builtinEach(args ...object.Object) object.Object {
+	arr, err := arrayArg("each", args, 2, 2)
+	if err != nil {
+		return err
+	}
+	if err := functionArg("each", args, 1); err != nil {
+		return err
+	}
+
+	for i, element := range arr.Snapshot() {
+		result := callback(args[1], element, i)
+		if isError(result) {
+			return result
+		}
+		if result == FALSE {
+			break
+		}
+	}
+	return NULL
+}
+
+// search chama fn para cada elemento até que o resultado seja want e
+// retorna o elemento encontrado, ou nil
+func search(name string, args []object.Object, want bool) (object.Object, object.Object) {
+	arr, err := arrayArg(name, args, 2, 2)
+	if err != nil {
+		return nil, err
+	}
+	if err := functionArg(name, args, 1); err != nil {
+		return nil, err
+	}
+
+	for i, element := range arr.Snapshot() {
+		result := callback(args[1], element, i)
+		if isError(result) {
+			return nil, result
+		}
+		if isTruthy(result) == want {
+			return element, nil
+		}
+	}
+	return nil, nil
+}
+
+func builtinFind(args ...object.Object) object.Object {
+	element, err := search("find", args, true)
+	if err != nil {
+		return err
+	}
+	if element == nil {
+		return NULL
+	}
+	return element
+}
+
+func builtinAny(args ...object.Object) object.Object {
+	element, err := search("any", args, true)
+	if err != nil {
+		return err
+	}
+	return nativeBoolToBooleanObject(element != nil)
+}
+
+func builtinAll(args ...object.Object) object.Object {
+	element, err := search("all", args, false)
+	if err != nil {
+		return err
+	}
+	return nativeBoolToBooleanObject(element == nil)
+}
+
+// builtinSort ordena números ou strings em ordem crescente. Com um
+// comparador fn(a, b), a vem antes de b se fn retornar true ou um número
+// negativo. A ordenação é estável
+func builtinSort(args ...object.Object) object.Object {
+	arr, err := arrayArg("sort", args, 1, 2)
+	if err != nil {
+		return err
+	}
+	if len(args) == 2 {
+		if err := functionArg("sort", args, 1); err != nil {
+			return err
+		}
+	}
+
+	result := arr.Snapshot()
+
+	// sort.SliceStable não pode ser interrompido: o primeiro erro é guardado
+	// e as comparações seguintes são ignoradas
+	var failure object.Object
+	less := func(a, b object.Object) bool {
+		if failure != nil {
+			return false
+		}
+		if len(args) == 1 {
+			switch {
+			case isNumber(a) && isNumber(b):
+				return toFloat(a) < toFloat(b)
+			case a.Type() == object.STRING_OBJ && b.Type() == object.STRING_OBJ:
+				return a.(*object.String).Value < b.(*object.String).Value
+			}
+			failure = newError("sort: cannot compare %s and %s", a.Type(), b.Type())
+			return false
+		}
+
+		result := applyFunction(args[1], []object.Object{a, b})
+		switch result := result.(type) {
+		case *object.Error:
+			failure = result
+		case *object.Boolean:
+			return result.Value
+		case *object.Integer, *object.Float:
+			return toFloat(result) < 0
+		default:
+			failure = newError("sort: comparator must return BOOLEAN or a number, got %s", result.Type())
+		}
+		return false
+	}
+
+	sort.SliceStable(result, func(i, j int) bool { return less(result[i], result[j]) })
+	if failure != nil {
+		return failure
+	}
+	return &object.Array{Elements: result}
+}
+
+func builtinReverse(args ...object.Object) object.Object {
+	arr, err := arrayArg("reverse", args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	elements := arr.Snapshot()
+	n := len(elements)
+	result := make([]object.Object, n)
+	for i, element := range elements {
+		result[n-1-i] = element
+	}
+	return &object.Array{Elements: result}
+}
+
+// builtinJoin junta os elementos, formatados como em print, com sep
+func builtinJoin(args ...object.Object) object.Object {
+	arr, err := arrayArg("join", args, 1, 2)
+	if err != nil {
+		return err
+	}
+
+	sep := ""
+	if len(args) == 2 {
+		s, ok := args[1].(*object.String)
+		if !ok {
+			return newError("separator to `join` must be STRING, got %s", args[1].Type())
+		}
+		sep = s.Value
+	}
+
+	elements := arr.Snapshot()
+	parts := make([]string, len(elements))
+	for i, element := range elements {
+		parts[i] = element.Inspect()
+	}
+	return &object.String{Value: strings.Join(parts, sep)}
+}
+
+// equals compara como ==, mas valores de tipos diferentes são apenas
+// diferentes em vez de um erro
+func equals(a, b object.Object) bool {
+	return evalInfixExpression("==", a, b) == TRUE
+}
+
+// indexOf retorna a posição da primeira ocorrência de args[1], ou -1
+func indexOf(name string, args []object.Object) (int, *object.Error) {
+	arr, err := arrayArg(name, args, 2, 2)
+	if err != nil {
+		return -1, err
+	}
+	for i, element := range arr.Snapshot() {
+		if equals(element, args[1]) {
+			return i, nil
+		}
+	}
+	return -1, nil
+}
+
+func builtinIndexOf(args ...object.Object) object.Object {
+	i, err := indexOf("indexOf", args)
+	if err != nil {
+		return err
+	}
+	return &object.Integer{Value: int64(i)}
+}
+
+func builtinContains(args ...object.Object) object.Object {
+	i, err := indexOf("contains", args)
+	if err != nil {
+		return err
+	}
+	return nativeBoolToBooleanObject(i >= 0)
+}
+
+// builtinFlatten remove um nível de aninhamento: [[1, 2], 3] vira [1, 2, 3]
+func builtinFlatten(args ...object.Object) object.Object {
+	arr, err := arrayArg("flatten", args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	result := []object.Object{}
+	for _, element := range arr.Snapshot() {
+		if inner, ok := element.(*object.Array); ok {
+			result = append(result, inner.Snapshot()...)
+		} else {
+			result = append(result, element)
+		}
+	}
+	return &object.Array{Elements: result}
+}
+
+// builtinZip agrupa os elementos de mesma posição. O resultado tem o
+// tamanho do menor array
+func builtinZip(args ...object.Object) object.Object {
+	if len(args) < 2 {
+		return newError("wrong number of arguments to `zip`. got=%d, want at least 2", len(args))
+	}
+
+	arrays := make([][]object.Object, len(args))
+	length := -1
+	for i, arg := range args {
+		arr, ok := arg.(*object.Array)
+		if !ok {
+			return newError("argument %d to `zip` must be ARRAY, got %s", i+1, arg.Type())
+		}
+		arrays[i] = arr.Snapshot()
+		if length < 0 || len(arrays[i]) < length {
+			length = len(arrays[i])
+		}
+	}
+
+	result := make([]object.Object, length)
+	for i := range result {
+		tuple := make([]object.Object, len(arrays))
+		for j, elements := range arrays {
+			tuple[j] = elements[i]
+		}
+		result[i] = &object.Array{Elements: tuple}
+	}
+	return &object.Array{Elements: result}
+}
+
+// builtinUnique remove repetições mantendo a primeira ocorrência. Valores
+// sem HashKey, como arrays e instâncias, são comparados por identidade
+func builtinUnique(args ...object.Object) object.Object {
+	arr, err := arrayArg("unique", args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	seen := map[object.HashKey]bool{}
+	seenObjects := map[object.Object]bool{}
+	result := []object.Object{}
+	for _, element := range arr.Snapshot() {
+		if hashable, ok := element.(object.Hashable); ok {
+			key := hashable.HashKey()
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		} else {
+			if seenObjects[element] {
+				continue
+			}
+			seenObjects[element] = true
+		}
+		result = append(result, element)
+	}
+	return &object.Array{Elements: result}
+}
+
+// maxArrayLength limita o tamanho dos arrays criados por range, como
+// maxStringLength faz com as strings
+const maxArrayLength = 1 << 24
+
+// builtinRange cria um array de inteiros: range(fim), range(inicio, fim) ou
+// range(inicio, fim, passo). fim não é incluído
+func builtinRange(args ...object.Object) object.Object {
+	if len(args) < 1 || len(args) > 3 {
+		return newError("wrong number of arguments to `range`. got=%d, want=1 to 3", len(args))
+	}
+
+	values := make([]int64, len(args))
+	for i, arg := range args {
+		n, ok := arg.(*object.Integer)
+		if !ok {
+			return newError("argument %d to `range` must be INTEGER, got %s", i+1, arg.Type())
+		}
+		values[i] = n.Value
+	}
+
+	start, end, step := int64(0), values[0], int64(1)
+	if len(values) > 1 {
+		start, end = values[0], values[1]
+	}
+	if len(values) > 2 {
+		step = values[2]
+	}
+	if step == 0 {
+		return newError("range step must not be zero")
+	}
+
+	// O tamanho é calculado antes, em uint64, para que nem o tamanho nem
+	// i += step estourem perto dos limites de int
+	var length uint64
+	if step > 0 && start < end {
+		length = (uint64(end)-uint64(start)-1)/uint64(step) + 1
+	} else if step < 0 && start > end {
+		length = (uint64(start)-uint64(end)-1)/(-uint64(step)) + 1
+	}
+	if length > maxArrayLength {
+		return newError("range: result would exceed %d elements", maxArrayLength)
+	}
+
+	result := make([]object.Object, length)
+	i := start
+	for k := range result {
+		result[k] = &object.Integer{Value: i}
+		i += step
+	}
+	return &object.Array{Elements: result}
+}
@@ -0,0 +1,101 @@
+package eval
+
+import (
+	"testing"
+
+	"jotlango/internal/lexer"
+	"jotlango/internal/object"
+	"jotlango/internal/parser"
+)
+
+func TestArrayBuiltins(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"map([1, 2, 3], fn(x) { x * 2 })", "[2, 4, 6]"},
+		{"map([\"a\", \"b\"], fn(x, i) { x + i })", "[a0, b1]"},
+		{"filter([1, 2, 3, 4], fn(x) { x % 2 == 0 })", "[2, 4]"},
+		{"reduce([1, 2, 3], fn(acc, x) { acc + x }, 10)", "16"},
+		{"find([1, 2, 3], fn(x) { x > 1 })", "2"},
+		{"sort([3, 1, 2], fn(a, b) { b < a })", "[3, 2, 1]"},
+		{"join(map(range(3), str), \"-\")", "0-1-2"},
+	}
+
+	for _, tt := range tests {
+		expectValue(t, tt.input, tt.want)
+	}
+}
+
+// Um erro dentro de um callback interrompe o builtin e chega a quem o
+// chamou com a posição do código do callback
+func TestArrayCallbackErrors(t *testing.T) {
+	tests := []struct {
+		input  string
+		column int
+	}{
+		{"map([1], fn(x) { x / 0 })", 20},
+		{"filter([1], fn(x) { x / 0 })", 23},
+		{"reduce([1, 2], fn(a, b) { a / 0 }, 0)", 29},
+		{"reduce([1, 2], fn(a, b) { a / 0 })", 29},
+		{"sort([2, 1], fn(a, b) { a / 0 })", 27},
+		{"find([1], fn(x) { x / 0 })", 21},
+		{"each([1], fn(x) { x / 0 })", 21},
+		{"any([1], fn(x) { x / 0 })", 20},
+		{"all([1], fn(x) { x / 0 })", 20},
+	}
+
+	for _, tt := range tests {
+		expectError(t, tt.input, "division by zero", 1, tt.column)
+	}
+
+	// Em uma função de várias linhas, a linha é a do callback
+	expectError(t, "var r = map([1, 2], fn(x) {\n    x / 0\n})", "division by zero", 2, 7)
+}
+
+func TestArrayCallbackErrorStopsIteration(t *testing.T) {
+	env := object.NewEnvironment()
+	run := func(input string) object.Object {
+		return Eval(parser.NewParser(lexer.NewLexer(input)).ParseProgram(), env)
+	}
+
+	run("var calls = 0")
+	result := run("map([1, 2, 3], fn(x) {\n    calls = calls + 1\n    if x == 2 { x / 0 } else { x }\n})")
+	if !isError(result) {
+		t.Fatalf("got %s, want an error", result.Inspect())
+	}
+	if calls := run("calls").Inspect(); calls != "2" {
+		t.Errorf("callback ran %s times, want 2", calls)
+	}
+}
+
+func TestRange(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"range(4)", "[0, 1, 2, 3]"},
+		{"range(0)", "[]"},
+		{"range(-2)", "[]"},
+		{"range(2, 5)", "[2, 3, 4]"},
+		{"range(0, 10, 3)", "[0, 3, 6, 9]"},
+		{"range(5, 0, -2)", "[5, 3, 1]"},
+		{"range(0, 5, -1)", "[]"},
+		// Perto dos limites de int, i += step não pode dar a volta
+		{"range(9223372036854775806, 9223372036854775807)", "[9223372036854775806]"},
+		{"range(9223372036854775800, 9223372036854775807, 5)", "[9223372036854775800, 9223372036854775805]"},
+		{"range(-9223372036854775807, -9223372036854775807 - 1, -1)", "[-9223372036854775807]"},
+		{"range(-9223372036854775807 - 1, 9223372036854775807, 9223372036854775807)", "[-9223372036854775808, -1, 9223372036854775806]"},
+		{"range(0, 9223372036854775807, 9223372036854775807)", "[0]"},
+		{"range(9223372036854775807, -9223372036854775807 - 1, -9223372036854775807 - 1)", "[9223372036854775807, -1]"},
+	}
+
+	for _, tt := range tests {
+		expectValue(t, tt.input, tt.want)
+	}
+
+	expectError(t, "range(0, 1, 0)", "range step must not be zero", 1, 6)
+	expectError(t, "range(9223372036854775807)", "range: result would exceed 16777216 elements", 1, 6)
+	expectError(t, "range(-9223372036854775807 - 1, 9223372036854775807)", "range: result would exceed 16777216 elements", 1, 6)
+	expectError(t, "range(1.5)", "argument 1 to `range` must be INTEGER, got FLOAT", 1, 6)
+}
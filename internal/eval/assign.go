@@ -0,0 +1,128 @@
+package eval
+
+import (
+	"jotlango/internal/ast"
+	"jotlango/internal/object"
+)
+
+// reference é um lugar que pode receber uma atribuição: uma variável, uma
+// propriedade, um elemento ou uma fatia. O objeto e o índice do alvo são
+// avaliados uma única vez, então em a[f()] += 1 a função f roda uma vez
+type reference struct {
+	get func() object.Object
+	set func(value object.Object) object.Object
+}
+
+// evalReference avalia as partes de um alvo de atribuição
+func evalReference(node ast.Expression, env *object.Environment) (*reference, object.Object) {
+	switch node := node.(type) {
+	case *ast.Identifier:
+		return &reference{
+			get: func() object.Object { return evalIdentifier(node, env) },
+			set: func(value object.Object) object.Object { return env.Assign(node.Value, value) },
+		}, nil
+	case *ast.PropertyExpression:
+		target := Eval(node.Object, env)
+		if isError(target) {
+			return nil, target
+		}
+		name := node.Property.Value
+		return &reference{
+			get: func() object.Object { return evalProperty(target, name) },
+			set: func(value object.Object) object.Object {
+				instance, ok := target.(*Instance)
+				if !ok {
+					return newError("cannot set property %s on %s", name, target.Type())
+				}
+				return instance.Set(name, value)
+			},
+		}, nil
+	case *ast.IndexExpression:
+		target := Eval(node.Left, env)
+		if isError(target) {
+			return nil, target
+		}
+		index := Eval(node.Index, env)
+		if isError(index) {
+			return nil, index
+		}
+		return &reference{
+			get: func() object.Object { return evalIndexExpression(target, index) },
+			set: func(value object.Object) object.Object { return evalIndexAssignment(target, index, value) },
+		}, nil
+	case *ast.SliceExpression:
+		target := Eval(node.Left, env)
+		if isError(target) {
+			return nil, target
+		}
+		start, end, err := evalSliceBounds(node, env)
+		if err != nil {
+			return nil, err
+		}
+		return &reference{
+			get: func() object.Object { return evalSliceExpression(target, start, end) },
+			set: func(value object.Object) object.Object { return evalSliceAssignment(target, start, end, value) },
+		}, nil
+	default:
+		return nil, newError("cannot assign to %s", node.String())
+	}
+}
+
+// evalAssignmentExpression avalia o alvo, depois o valor. Em uma atribuição
+// composta, a += b, o valor atual do alvo é lido antes de avaliar b
+func evalAssignmentExpression(node *ast.AssignmentExpression, env *object.Environment) object.Object {
+	ref, err := evalReference(node.Left, env)
+	if err != nil {
+		return err
+	}
+
+	var current object.Object
+	if node.Operator != "" {
+		current = ref.get()
+		if isError(current) {
+			return current
+		}
+	}
+
+	value := Eval(node.Value, env)
+	if isError(value) {
+		return value
+	}
+
+	if node.Operator != "" {
+		value = evalInfixExpression(node.Operator, current, value)
+		if isError(value) {
+			return value
+		}
+	}
+
+	return ref.set(value)
+}
+
+// evalPostfixExpression soma ou subtrai 1 do alvo e retorna o valor
+// anterior, como em C: se x é 1, x++ vale 1 e x passa a valer 2
+func evalPostfixExpression(node *ast.PostfixExpression, env *object.Environment) object.Object {
+	ref, err := evalReference(node.Left, env)
+	if err != nil {
+		return err
+	}
+
+	current := ref.get()
+	if isError(current) {
+		return current
+	}
+
+	operator := node.Operator[:1]
+	if !isNumber(current) {
+		return newError("unknown operator: %s%s", current.Type(), node.Operator)
+	}
+	value := evalInfixExpression(operator, current, &object.Integer{Value: 1})
+	if isError(value) {
+		return value
+	}
+
+	if result := ref.set(value); isError(result) {
+		return result
+	}
+	return current
+}
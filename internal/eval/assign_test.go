@@ -0,0 +1,62 @@
+package eval
+
+import "testing"
+
+func TestCompoundAssignment(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"var x = 10\nx += 5\nx -= 3\nx *= 2\nx /= 4\nx %= 4\nx", "2"},
+		{"var s = \"a\"\ns += \"b\"\ns", "ab"},
+		{"var f = 1.5\nf += 1\nf", "2.5"},
+		{"var a = [1, 2]\na[0] += 10\na[-1] *= 3\na", "[11, 6]"},
+		{"var h = {\"n\": 1}\nh[\"n\"] -= 4\nh[\"n\"]", "-3"},
+		{"class C { prop int N = 1 }\nvar c = new C()\nc.N += 4\nc.N", "5"},
+		{"var x = 1\nvar y = x += 2\ny", "3"},
+	}
+
+	for _, tt := range tests {
+		expectValue(t, tt.input, tt.want)
+	}
+}
+
+func TestPostfixIncrement(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"var x = 1\nx++\nx", "2"},
+		{"var x = 1\nx--\nx", "0"},
+		{"var x = 1\nvar old = x++\nvar r = [old, x]\nr", "[1, 2]"},
+		{"var a = [5]\na[0]++\na", "[6]"},
+		{"class C { prop int N = 1 }\nvar c = new C()\nc.N--\nc.N", "0"},
+		{"var f = 0.5\nf++\nf", "1.5"},
+	}
+
+	for _, tt := range tests {
+		expectValue(t, tt.input, tt.want)
+	}
+}
+
+func TestAssignmentTargetEvaluatedOnce(t *testing.T) {
+	input := `var calls = 0
+var a = [0, 0]
+fn index() {
+    calls = calls + 1
+    return 1
+}
+a[index()] += 5
+a[index()]++
+var r = [a, calls]
+r`
+
+	expectValue(t, input, "[[0, 6], 2]")
+}
+
+func TestAssignmentErrors(t *testing.T) {
+	expectError(t, "z += 1", "identificador não encontrado: z", 1, 3)
+	expectError(t, "var s = \"a\"\ns++", "unknown operator: STRING++", 2, 2)
+	expectError(t, "var n = 9223372036854775807\nn++", "integer overflow: 9223372036854775807 + 1", 2, 2)
+	expectError(t, "var s = \"a\"\ns -= 1", "type mismatch: STRING - INTEGER (convert with int(), float() or str())", 2, 3)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"jotlango/internal/object"
 	"strings"
+	"unicode/utf8"
 )
 
 var builtins = map[string]*object.Builtin{
@@ -17,6 +18,14 @@ var builtins = map[string]*object.Builtin{
 			return object.NULL
 		},
 	},
+	"type": {
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newError("wrong number of arguments. got=%d, want=1", len(args))
+			}
+			return &object.String{Value: string(args[0].Type())}
+		},
+	},
 	"len": {
 		Fn: func(args ...object.Object) object.Object {
 			if len(args) != 1 {
@@ -25,9 +34,12 @@ var builtins = map[string]*object.Builtin{
 
 			switch arg := args[0].(type) {
 			case *object.String:
-				return &object.Number{Value: float64(len(arg.Value))}
+				// Conta caracteres, não bytes
+				return &object.Integer{Value: int64(utf8.RuneCountInString(arg.Value))}
 			case *object.Array:
-				return &object.Number{Value: float64(len(arg.Elements))}
+				return &object.Integer{Value: int64(arg.Len())}
+			case *object.Hash:
+				return &object.Integer{Value: int64(arg.Len())}
 			default:
 				return newError("argument to `len` not supported, got %s", args[0].Type())
 			}
@@ -44,8 +56,8 @@ var builtins = map[string]*object.Builtin{
 			}
 
 			arr := args[0].(*object.Array)
-			if len(arr.Elements) > 0 {
-				return arr.Elements[0]
+			if element, ok := arr.Get(0); ok {
+				return element
 			}
 
 			return NULL
@@ -62,9 +74,8 @@ var builtins = map[string]*object.Builtin{
 			}
 
 			arr := args[0].(*object.Array)
-			length := len(arr.Elements)
-			if length > 0 {
-				return arr.Elements[length-1]
+			if element, ok := arr.Get(arr.Len() - 1); ok {
+				return element
 			}
 
 			return NULL
@@ -81,11 +92,9 @@ var builtins = map[string]*object.Builtin{
 			}
 
 			arr := args[0].(*object.Array)
-			length := len(arr.Elements)
-			if length > 0 {
-				newElements := make([]object.Object, length-1)
-				copy(newElements, arr.Elements[1:])
-				return &object.Array{Elements: newElements}
+			elements := arr.Snapshot()
+			if len(elements) > 0 {
+				return &object.Array{Elements: elements[1:]}
 			}
 
 			return NULL
@@ -102,9 +111,7 @@ var builtins = map[string]*object.Builtin{
 			}
 
 			arr := args[0].(*object.Array)
-			newElements := make([]object.Object, len(arr.Elements)+1)
-			copy(newElements, arr.Elements)
-			newElements[len(arr.Elements)] = args[1]
+			newElements := append(arr.Snapshot(), args[1])
 
 			return &object.Array{Elements: newElements}
 		},
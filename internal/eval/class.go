@@ -3,33 +3,165 @@ package eval
 import (
 	"bytes"
 	"fmt"
+	"jotlango/internal/ast"
 	"jotlango/internal/object"
 )
 
 type Class struct {
 	Name       string
-	Properties map[string]object.Object
+	Parent     *Class
+	Interfaces []*Interface
+	Properties map[string]ast.Expression // valores padrão; nil quando não há
 	Methods    map[string]*object.Function
+	Env        *object.Environment // ambiente onde a classe foi declarada
+
+	propertyOrder []string
+	methodOrder   []string
 }
 
-func (c *Class) Type() object.ObjectType { return "CLASS" }
+func (c *Class) Type() object.ObjectType { return object.CLASS_OBJ }
 func (c *Class) Inspect() string {
 	var out bytes.Buffer
-	out.WriteString("class " + c.Name + " {")
-	for name := range c.Properties {
+	out.WriteString("class " + c.Name)
+	if c.Parent != nil {
+		out.WriteString(" : " + c.Parent.Name)
+	}
+	out.WriteString(" {")
+	for _, name := range c.propertyOrder {
 		out.WriteString(fmt.Sprintf("\n  prop %s", name))
 	}
-	for name := range c.Methods {
+	for _, name := range c.methodOrder {
 		out.WriteString(fmt.Sprintf("\n  fn %s", name))
 	}
 	out.WriteString("\n}")
 	return out.String()
 }
 
-func NewClass(name string) *Class {
+func NewClass(name string, env *object.Environment) *Class {
 	return &Class{
 		Name:       name,
-		Properties: make(map[string]object.Object),
+		Properties: make(map[string]ast.Expression),
 		Methods:    make(map[string]*object.Function),
+		Env:        env,
+	}
+}
+
+// AddProperty declara uma propriedade com valor padrão opcional
+func (c *Class) AddProperty(name string, value ast.Expression) {
+	if _, ok := c.Properties[name]; !ok {
+		c.propertyOrder = append(c.propertyOrder, name)
+	}
+	c.Properties[name] = value
+}
+
+// AddMethod declara um método
+func (c *Class) AddMethod(name string, fn *object.Function) {
+	if _, ok := c.Methods[name]; !ok {
+		c.methodOrder = append(c.methodOrder, name)
+	}
+	c.Methods[name] = fn
+}
+
+// FindMethod procura um método na classe e em suas superclasses, retornando
+// também a classe que o define
+func (c *Class) FindMethod(name string) (*object.Function, *Class, bool) {
+	for class := c; class != nil; class = class.Parent {
+		if fn, ok := class.Methods[name]; ok {
+			return fn, class, true
+		}
 	}
+	return nil, nil, false
 }
+
+// IsSubclassOf indica se a classe é other ou descende dela
+func (c *Class) IsSubclassOf(other *Class) bool {
+	for class := c; class != nil; class = class.Parent {
+		if class == other {
+			return true
+		}
+	}
+	return false
+}
+
+// Implements indica se a classe ou uma superclasse declara a interface
+func (c *Class) Implements(iface *Interface) bool {
+	for class := c; class != nil; class = class.Parent {
+		for _, i := range class.Interfaces {
+			if i == iface {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// CheckInterface verifica se a classe fornece todos os métodos da interface
+// com o número de parâmetros esperado
+func (c *Class) CheckInterface(iface *Interface) error {
+	for _, name := range iface.methodOrder {
+		method, _, ok := c.FindMethod(name)
+		if !ok {
+			return fmt.Errorf("class %s does not implement %s: missing method %s", c.Name, iface.Name, name)
+		}
+		if want := iface.Methods[name]; len(method.Parameters) != want {
+			return fmt.Errorf("class %s does not implement %s: method %s takes %d parameters, want %d",
+				c.Name, iface.Name, name, len(method.Parameters), want)
+		}
+	}
+	return nil
+}
+
+// Interface é um contrato de métodos verificado quando a classe é declarada
+type Interface struct {
+	Name    string
+	Methods map[string]int // nome do método -> número de parâmetros
+
+	methodOrder []string
+}
+
+func (i *Interface) Type() object.ObjectType { return object.INTERFACE_OBJ }
+func (i *Interface) Inspect() string {
+	var out bytes.Buffer
+	out.WriteString("interface " + i.Name + " {")
+	for _, name := range i.methodOrder {
+		out.WriteString(fmt.Sprintf("\n  fn %s/%d", name, i.Methods[name]))
+	}
+	out.WriteString("\n}")
+	return out.String()
+}
+
+func NewInterface(name string) *Interface {
+	return &Interface{Name: name, Methods: make(map[string]int)}
+}
+
+// AddMethod declara um método da interface
+func (i *Interface) AddMethod(name string, arity int) {
+	if _, ok := i.Methods[name]; !ok {
+		i.methodOrder = append(i.methodOrder, name)
+	}
+	i.Methods[name] = arity
+}
+
+// BoundMethod é um método associado à instância que será `this` na chamada.
+// Owner é a classe que define o método, usada para resolver `super`
+type BoundMethod struct {
+	Receiver *Instance
+	Owner    *Class
+	Method   *object.Function
+	Name     string
+}
+
+func (bm *BoundMethod) Type() object.ObjectType { return object.BOUND_METHOD_OBJ }
+func (bm *BoundMethod) Inspect() string {
+	return fmt.Sprintf("method %s.%s", bm.Receiver.Class.Name, bm.Name)
+}
+
+// Super é o valor de `super` dentro de um método: dá acesso aos métodos da
+// superclasse da classe que define o método, mantendo o mesmo receptor
+type Super struct {
+	Receiver *Instance
+	Class    *Class
+}
+
+func (s *Super) Type() object.ObjectType { return object.SUPER_OBJ }
+func (s *Super) Inspect() string         { return "super " + s.Class.Name }
@@ -0,0 +1,165 @@
+package eval
+
+import (
+	"strings"
+	"testing"
+)
+
+// Funções, métodos e construtores com corpo vazio retornam null
+func TestEmptyBodies(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"fn f() {}\nf()", "null"},
+		{"fn f() {}\nstr(f())", "null"},
+		{"var f = fn() {}\nf()", "null"},
+		{"fn f() {}\nvar r = [f(), 1]\nr", "[null, 1]"},
+		{"class A {\n    fn Nada() {}\n}\nnew A().Nada()", "null"},
+		{"class A {\n    prop x = 1\n    fn New() {}\n}\nnew A().x", "1"},
+		{"map([1, 2], fn(x) {})", "[null, null]"},
+	}
+
+	for _, tt := range tests {
+		expectValue(t, tt.input, tt.want)
+	}
+}
+
+const pointClass = `class Ponto {
+    prop x: int = 0
+    prop y = 0
+    prop tags = []
+
+    fn New(x, y) {
+        this.x = x
+        this.y = y
+    }
+
+    fn soma() { return this.x + this.y }
+
+    fn mover(dx) {
+        this.x += dx
+        return this
+    }
+}
+`
+
+func TestClasses(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"new Ponto(1, 2).soma()", "3"},
+		{"new Ponto(1, 2).mover(5).mover(1).x", "7"},
+		{"var p = new Ponto(1, 2)\np.y = 10\np.soma()", "11"},
+		{"var p = new Ponto(1, 2)\np.y += 3\np.y", "5"},
+		{"new Ponto(1, 2)", "Ponto {\n  x: 1\n  y: 2\n  tags: []\n}"},
+
+		// Cada instância tem seus próprios valores padrão
+		{"var a = new Ponto(0, 0)\nvar b = new Ponto(0, 0)\na.tags = push(a.tags, 1)\nvar r = [a.tags, b.tags]\nr", "[[1], []]"},
+
+		// Sem construtor, as propriedades ficam com o valor padrão; atribuir
+		// uma propriedade não declarada a cria
+		{"class Vazio { prop n = 3 }\nnew Vazio().n", "3"},
+		{"class Vazio { prop n }\nnew Vazio().n", "null"},
+		{"class Vazio {}\nvar v = new Vazio()\nv.extra = 9\nv", "Vazio {\n  extra: 9\n}"},
+
+		// Um método lido sem ser chamado continua ligado à instância
+		{"var p = new Ponto(4, 5)\nvar f = p.soma\nf()", "9"},
+		{"var p = new Ponto(1, 1)\nmap([1, 2], p.mover)\np.x", "4"},
+
+		// O valor de retorno do construtor é ignorado
+		{"class A {\n    prop x = 1\n    fn New() { return 5 }\n}\nnew A().x", "1"},
+	}
+
+	for _, tt := range tests {
+		expectValue(t, pointClass+tt.input, tt.want)
+	}
+}
+
+func TestClassErrors(t *testing.T) {
+	line := func(n int) int { return strings.Count(pointClass, "\n") + n }
+
+	expectError(t, pointClass+"new Ponto(1)", "wrong number of arguments to Ponto.New. got=1, want=2", line(1), 1)
+	expectError(t, pointClass+"new Ponto(1, 2).z", "undefined property z on Ponto", line(1), 16)
+	expectError(t, pointClass+"new Ponto(1, 2).desenhar()", "undefined property desenhar on Ponto", line(1), 16)
+	expectError(t, "class A {}\nnew A(1)", "class A has no constructor New but got 1 arguments", 2, 1)
+	expectError(t, "new Nada()", "identificador não encontrado: Nada", 1, 5)
+	expectError(t, "var x = 1\nx.y", "property access not supported: INTEGER.y", 2, 2)
+	expectError(t, "var x = 1\nx.y = 2", "cannot set property y on INTEGER", 2, 5)
+	expectError(t, "this", "identificador não encontrado: this", 1, 1)
+}
+
+const userClasses = `class Usuario {
+    prop nome = ""
+    fn New(nome) { this.nome = nome }
+    fn descricao() { return "usuário " + this.nome }
+    fn tipo() { return "comum" }
+}
+
+class Admin : Usuario {
+    prop nivel = 1
+    fn New(nome, nivel) {
+        super.New(nome)
+        this.nivel = nivel
+    }
+    fn tipo() { return "admin " + super.tipo() }
+}
+
+class Root : Admin {
+    fn tipo() { return "root/" + super.tipo() }
+}
+`
+
+func TestInheritance(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		// Métodos herdados, sobrescritos e chamados com super
+		{"new Admin(\"ana\", 2).descricao()", "usuário ana"},
+		{"new Admin(\"ana\", 2).tipo()", "admin comum"},
+		{"new Usuario(\"bia\").tipo()", "comum"},
+		// super se refere à superclasse de quem define o método, não da instância
+		{"new Root(\"r\", 9).tipo()", "root/admin comum"},
+		// O construtor também é herdado
+		{"new Root(\"r\", 9).nivel", "9"},
+		// Propriedades da superclasse vêm primeiro
+		{"new Admin(\"ana\", 2)", "Admin {\n  nome: ana\n  nivel: 2\n}"},
+	}
+
+	for _, tt := range tests {
+		expectValue(t, userClasses+tt.input, tt.want)
+	}
+
+	expectError(t, "class B {}\nclass A : B {\n    fn f() { super.g() }\n}\nnew A().f()",
+		"undefined method g on superclass B", 3, 19)
+	expectError(t, "class A {\n    fn f() { super.f() }\n}\nnew A().f()",
+		"identificador não encontrado: super", 2, 14)
+	expectError(t, "class A : Nada {}", "identificador não encontrado: Nada", 1, 11)
+	expectError(t, "var x = 1\nclass A : x {}", "class A cannot extend INTEGER: not a class or interface", 2, 1)
+}
+
+func TestInterfaces(t *testing.T) {
+	const named = "interface Nomeado {\n    fn nome(): string\n    fn saudar(outro)\n}\n"
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"class P : Nomeado {\n    fn nome() { \"p\" }\n    fn saudar(o) { \"oi \" + o }\n}\nnew P().saudar(\"x\")", "oi x"},
+		// Um método herdado da superclasse satisfaz a interface
+		{"class B {\n    fn nome() { \"b\" }\n    fn saudar(o) { o }\n}\nclass A : B, Nomeado {}\nnew A().nome()", "b"},
+		{"Nomeado", "interface Nomeado {\n  fn nome/0\n  fn saudar/1\n}"},
+	}
+	for _, tt := range tests {
+		expectValue(t, named+tt.input, tt.want)
+	}
+
+	// A verificação acontece na definição da classe, não no uso
+	expectError(t, named+"class A : Nomeado {\n    fn nome() { \"a\" }\n}",
+		"class A does not implement Nomeado: missing method saudar", 5, 1)
+	expectError(t, named+"class A : Nomeado {\n    fn nome() { \"a\" }\n    fn saudar() {}\n}",
+		"class A does not implement Nomeado: method saudar takes 0 parameters, want 1", 5, 1)
+	expectError(t, named+"new Nomeado()", "cannot instantiate INTERFACE: not a class", 5, 1)
+}
@@ -0,0 +1,143 @@
+package eval
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"jotlango/internal/object"
+)
+
+// Regras de conversão entre tipos. A única conversão implícita é a de +
+// com uma string de um dos lados: o outro operando é convertido como em
+// str(), então "Total: " + 3 é "Total: 3". Os demais operadores exigem
+// tipos compatíveis, e == entre tipos diferentes é false (exceto entre
+// int e float, comparados pelo valor)
+
+func init() {
+	for name, fn := range map[string]object.BuiltinFunction{
+		"str":   builtinStr,
+		"int":   builtinInt,
+		"float": builtinFloat,
+		"bool":  builtinBool,
+	} {
+		builtins[name] = &object.Builtin{Fn: fn}
+	}
+}
+
+// str converte qualquer valor para string, no mesmo formato de print
+func builtinStr(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments to str. got=%d, want=1", len(args))
+	}
+	return &object.String{Value: args[0].Inspect()}
+}
+
+// int converte um float (truncando em direção a zero), uma string com um
+// inteiro em base 10 ou um bool (1 ou 0)
+func builtinInt(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments to int. got=%d, want=1", len(args))
+	}
+
+	switch arg := args[0].(type) {
+	case *object.Integer:
+		return arg
+	case *object.Float:
+		if math.IsNaN(arg.Value) || arg.Value >= math.MaxInt64 || arg.Value < math.MinInt64 {
+			return newError("cannot convert %s to int: out of range", arg.Inspect())
+		}
+		return &object.Integer{Value: int64(arg.Value)}
+	case *object.String:
+		n, err := strconv.ParseInt(strings.TrimSpace(arg.Value), 10, 64)
+		if err != nil {
+			if isRangeError(err) {
+				return newError("cannot convert %q to int: out of range", arg.Value)
+			}
+			return newError("cannot convert %q to int", arg.Value)
+		}
+		return &object.Integer{Value: n}
+	case *object.Boolean:
+		if arg.Value {
+			return &object.Integer{Value: 1}
+		}
+		return &object.Integer{Value: 0}
+	default:
+		return newError("cannot convert %s to int", args[0].Type())
+	}
+}
+
+// float converte um int, uma string com um número ou um bool (1.0 ou 0.0)
+func builtinFloat(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments to float. got=%d, want=1", len(args))
+	}
+
+	switch arg := args[0].(type) {
+	case *object.Float:
+		return arg
+	case *object.Integer:
+		return &object.Float{Value: float64(arg.Value)}
+	case *object.String:
+		f, err := strconv.ParseFloat(strings.TrimSpace(arg.Value), 64)
+		if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+			return newError("cannot convert %q to float", arg.Value)
+		}
+		return &object.Float{Value: f}
+	case *object.Boolean:
+		if arg.Value {
+			return &object.Float{Value: 1}
+		}
+		return &object.Float{Value: 0}
+	default:
+		return newError("cannot convert %s to float", args[0].Type())
+	}
+}
+
+// bool converte as strings "true" e "false" e números (diferente de zero é
+// true). Não confundir com a veracidade usada em if, em que só null e false
+// são falsos
+func builtinBool(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments to bool. got=%d, want=1", len(args))
+	}
+
+	switch arg := args[0].(type) {
+	case *object.Boolean:
+		return arg
+	case *object.Integer, *object.Float:
+		return nativeBoolToBooleanObject(toFloat(arg) != 0)
+	case *object.String:
+		switch strings.TrimSpace(arg.Value) {
+		case "true":
+			return TRUE
+		case "false":
+			return FALSE
+		}
+		return newError("cannot convert %q to bool", arg.Value)
+	default:
+		return newError("cannot convert %s to bool", args[0].Type())
+	}
+}
+
+func isRangeError(err error) bool {
+	numErr, ok := err.(*strconv.NumError)
+	return ok && numErr.Err == strconv.ErrRange
+}
+
+// evalMixedInfixExpression trata operandos de tipos diferentes que não são
+// ambos números
+func evalMixedInfixExpression(operator string, left, right object.Object) object.Object {
+	switch {
+	case operator == "+" && (left.Type() == object.STRING_OBJ || right.Type() == object.STRING_OBJ):
+		return &object.String{Value: left.Inspect() + right.Inspect()}
+	case operator == "==":
+		return FALSE
+	case operator == "!=":
+		return TRUE
+	case isNumber(left) && right.Type() == object.STRING_OBJ || left.Type() == object.STRING_OBJ && isNumber(right):
+		return newError("type mismatch: %s %s %s (convert with int(), float() or str())", left.Type(), operator, right.Type())
+	default:
+		return newError("type mismatch: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
@@ -0,0 +1,109 @@
+package eval
+
+import "testing"
+
+func TestConversions(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"str(3.0)", "3.0"},
+		{"str(null)", "null"},
+		{`str([1, "a"])`, "[1, a]"},
+		{"str(true) + \"!\"", "true!"},
+
+		{"int(3.9)", "3"},
+		{"int(-3.9)", "-3"},
+		{`int("42")`, "42"},
+		{`int(" -7 ")`, "-7"},
+		{"int(true)", "1"},
+		{"int(false)", "0"},
+		{"int(5)", "5"},
+
+		{"float(2)", "2.0"},
+		{`float("2.5")`, "2.5"},
+		{`float("1e3")`, "1000.0"},
+		{"float(true)", "1.0"},
+
+		{`bool("true")`, "true"},
+		{`bool("false")`, "false"},
+		{"bool(0)", "false"},
+		{"bool(0.5)", "true"},
+		{"bool(true)", "true"},
+	}
+
+	for _, tt := range tests {
+		expectValue(t, tt.input, tt.want)
+	}
+
+	errors := []struct {
+		input   string
+		message string
+		column  int
+	}{
+		{`int("3.5")`, `cannot convert "3.5" to int`, 4},
+		{`int("abc")`, `cannot convert "abc" to int`, 4},
+		{"int(null)", "cannot convert NULL to int", 4},
+		{"int(9223372036854775807.0)", "cannot convert 9223372036854776000.0 to int: out of range", 4},
+		{`float("x")`, `cannot convert "x" to float`, 6},
+		{`float("NaN")`, `cannot convert "NaN" to float`, 6},
+		{`bool("yes")`, `cannot convert "yes" to bool`, 5},
+		{"bool(null)", "cannot convert NULL to bool", 5},
+		{"str()", "wrong number of arguments to str. got=0, want=1", 4},
+		{"int(1, 2)", "wrong number of arguments to int. got=2, want=1", 4},
+	}
+	for _, tt := range errors {
+		expectError(t, tt.input, tt.message, 1, tt.column)
+	}
+}
+
+// + com uma string de um dos lados converte o outro como str(); os demais
+// operadores exigem tipos compatíveis
+func TestMixedTypeOperators(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`"Tamanho: " + len([1, 2, 3])`, "Tamanho: 3"},
+		{`1 + "a"`, "1a"},
+		{`"x" + null`, "xnull"},
+		{`"v" + 1.5 + true`, "v1.5true"},
+		{`"h" + {"a": 1}`, "h{a: 1}"},
+		{`1 + 2 + "a"`, "3a"},
+	}
+
+	for _, tt := range tests {
+		expectValue(t, tt.input, tt.want)
+	}
+
+	expectError(t, `"2" * 3`, "type mismatch: STRING * INTEGER (convert with int(), float() or str())", 1, 5)
+	expectError(t, `1 < "2"`, "type mismatch: INTEGER < STRING (convert with int(), float() or str())", 1, 3)
+	expectError(t, "[1] - 1", "type mismatch: ARRAY - INTEGER", 1, 5)
+}
+
+// == entre tipos diferentes é false em vez de erro, exceto entre números
+func TestCrossTypeEquality(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`1 == "1"`, "false"},
+		{`1 != "1"`, "true"},
+		{"1 == 1.0", "true"},
+		{"2.5 != 2", "true"},
+		{"null == false", "false"},
+		{"null == null", "true"},
+		{"0 == false", "false"},
+		{`"a" == "a"`, "true"},
+		{`[1] == "[1]"`, "false"},
+		// Arrays, hashes e instâncias são comparados por identidade
+		{"[1] == [1]", "false"},
+		{"var a = [1]\na == a", "true"},
+		{"contains([1, \"1\", true], \"1\")", "true"},
+		{"indexOf([\"1\", 1], 1)", "1"},
+	}
+
+	for _, tt := range tests {
+		expectValue(t, tt.input, tt.want)
+	}
+}
@@ -2,27 +2,23 @@ package eval
 
 import (
 	"fmt"
+	"path/filepath"
 
 	"jotlango/internal/ast"
+	"jotlango/internal/diag"
 	"jotlango/internal/object"
 )
 
+// Os mesmos valores de object, para que builtins que retornam object.NULL
+// sejam iguais a null
 var (
-	TRUE  = &object.Boolean{Value: true}
-	FALSE = &object.Boolean{Value: false}
-	NULL  = &object.Null{}
-)
+	TRUE  = object.TRUE
+	FALSE = object.FALSE
+	NULL  = object.NULL
 
-var builtins = map[string]*object.Builtin{
-	"print": {
-		Fn: func(args ...object.Object) object.Object {
-			for _, arg := range args {
-				fmt.Println(arg.Inspect())
-			}
-			return NULL
-		},
-	},
-}
+	BREAK    = &object.Break{}
+	CONTINUE = &object.Continue{}
+)
 
 type Evaluator struct {
 	env *object.Environment
@@ -34,11 +30,51 @@ func NewEvaluator() *Evaluator {
 	}
 }
 
+// NewFileEvaluator cria um avaliador para o arquivo principal de um projeto.
+// Os imports são resolvidos a partir do jot.json mais próximo de file
+func NewFileEvaluator(file string) (*Evaluator, error) {
+	file, err := filepath.Abs(file)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := FindProject(filepath.Dir(file))
+	if err != nil {
+		return nil, err
+	}
+
+	loader := NewLoader(project)
+	loader.enter(file)
+
+	return &Evaluator{
+		env: object.NewModuleEnvironment(file, loader),
+	}, nil
+}
+
 func (e *Evaluator) Eval(node ast.Node) object.Object {
 	return Eval(node, e.env)
 }
 
 func Eval(node ast.Node, env *object.Environment) object.Object {
+	result := evalNode(node, env)
+
+	// Erros recebem a posição do nó mais interno que os produziu. Um erro
+	// da biblioteca padrão passa a apontar para a chamada no código do
+	// usuário, que é onde ele pode ser corrigido
+	if err, ok := result.(*object.Error); ok {
+		switch pos := node.Pos(); {
+		case !err.Pos.IsValid():
+			err.Pos = pos
+		case inStdlib(err.Pos) && pos.IsValid() && !inStdlib(pos):
+			err.Origin = err.Pos
+			err.Pos = pos
+		}
+	}
+
+	return result
+}
+
+func evalNode(node ast.Node, env *object.Environment) object.Object {
 	switch node := node.(type) {
 	case *ast.Program:
 		return evalProgram(node, env)
@@ -46,6 +82,10 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		return Eval(node.Expression, env)
 	case *ast.ClassStatement:
 		return evalClassStatement(node, env)
+	case *ast.InterfaceStatement:
+		return evalInterfaceStatement(node, env)
+	case *ast.ImportStatement:
+		return evalImportStatement(node, env)
 	case *ast.CallStatement:
 		return evalCallStatement(node, env)
 	case *ast.FunctionStatement:
@@ -55,25 +95,33 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 	case *ast.ReturnStatement:
 		return evalReturnStatement(node, env)
 	case *ast.CallExpression:
-		if node.Function.TokenLiteral() == "print" {
-			args := evalExpressions(node.Arguments, env)
-			if len(args) == 1 {
-				fmt.Println(args[0].Inspect())
-				return args[0]
-			}
-			return nil
-		}
 		function := Eval(node.Function, env)
+		if isError(function) {
+			return function
+		}
 		args := evalExpressions(node.Arguments, env)
+		if len(args) == 1 && isError(args[0]) {
+			return args[0]
+		}
 		return applyFunction(function, args)
 	case *ast.Identifier:
 		return evalIdentifier(node, env)
 	case *ast.StringLiteral:
 		return &object.String{Value: node.Value}
+	case *ast.TemplateLiteral:
+		return evalTemplateLiteral(node, env)
 	case *ast.NumberLiteral:
-		return &object.Number{Value: node.Value}
+		return &object.Float{Value: node.Value}
+	case *ast.IntegerLiteral:
+		return &object.Integer{Value: node.Value}
+	case *ast.FloatLiteral:
+		return &object.Float{Value: node.Value}
 	case *ast.Boolean:
 		return nativeBoolToBooleanObject(node.Value)
+	case *ast.NullLiteral:
+		return NULL
+	case *ast.FunctionLiteral:
+		return &object.Function{Parameters: node.Parameters, Body: node.Body, Env: env}
 	case *ast.PrefixExpression:
 		right := Eval(node.Right, env)
 		if isError(right) {
@@ -81,6 +129,9 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		}
 		return evalPrefixExpression(node.Operator, right)
 	case *ast.InfixExpression:
+		if node.Operator == "&&" || node.Operator == "||" {
+			return evalLogicalExpression(node, env)
+		}
 		left := Eval(node.Left, env)
 		if isError(left) {
 			return left
@@ -92,6 +143,16 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		return evalInfixExpression(node.Operator, left, right)
 	case *ast.BlockStatement:
 		return evalBlockStatement(node, env)
+	case *ast.IfExpression:
+		return evalIfExpression(node, env)
+	case *ast.WhileStatement:
+		return evalWhileStatement(node, env)
+	case *ast.ForStatement:
+		return evalForStatement(node, env)
+	case *ast.BreakStatement:
+		return BREAK
+	case *ast.ContinueStatement:
+		return CONTINUE
 	case *ast.ArrayLiteral:
 		elements := evalExpressions(node.Elements, env)
 		if len(elements) == 1 && isError(elements[0]) {
@@ -100,6 +161,14 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		return &object.Array{Elements: elements}
 	case *ast.HashLiteral:
 		return evalHashLiteral(node, env)
+	case *ast.NewExpression:
+		return evalNewExpression(node, env)
+	case *ast.PropertyExpression:
+		return evalPropertyExpression(node, env)
+	case *ast.AssignmentExpression:
+		return evalAssignmentExpression(node, env)
+	case *ast.PostfixExpression:
+		return evalPostfixExpression(node, env)
 	case *ast.IndexExpression:
 		left := Eval(node.Left, env)
 		if isError(left) {
@@ -110,6 +179,16 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 			return index
 		}
 		return evalIndexExpression(left, index)
+	case *ast.SliceExpression:
+		left := Eval(node.Left, env)
+		if isError(left) {
+			return left
+		}
+		start, end, err := evalSliceBounds(node, env)
+		if err != nil {
+			return err
+		}
+		return evalSliceExpression(left, start, end)
 	}
 
 	return nil
@@ -149,9 +228,14 @@ func evalExpressions(exps []ast.Expression, env *object.Environment) []object.Ob
 func applyFunction(fn object.Object, args []object.Object) object.Object {
 	switch fn := fn.(type) {
 	case *object.Function:
+		if len(args) != len(fn.Parameters) {
+			return newError("wrong number of arguments. got=%d, want=%d", len(args), len(fn.Parameters))
+		}
 		extendedEnv := extendFunctionEnv(fn, args)
 		evaluated := Eval(fn.Body, extendedEnv)
 		return unwrapReturnValue(evaluated)
+	case *BoundMethod:
+		return applyMethod(fn.Receiver, fn.Owner, fn.Name, fn.Method, args)
 	case *object.Builtin:
 		return fn.Fn(args...)
 	default:
@@ -159,6 +243,24 @@ func applyFunction(fn object.Object, args []object.Object) object.Object {
 	}
 }
 
+// applyMethod chama um método com `this` associado ao receptor e `super`
+// associado à superclasse de owner, a classe que define o método
+func applyMethod(receiver *Instance, owner *Class, name string, method *object.Function, args []object.Object) object.Object {
+	if len(args) != len(method.Parameters) {
+		return newError("wrong number of arguments to %s.%s. got=%d, want=%d",
+			owner.Name, name, len(args), len(method.Parameters))
+	}
+
+	extendedEnv := extendFunctionEnv(method, args)
+	extendedEnv.Set("this", receiver)
+	if owner.Parent != nil {
+		extendedEnv.Set("super", &Super{Receiver: receiver, Class: owner.Parent})
+	}
+
+	evaluated := Eval(method.Body, extendedEnv)
+	return unwrapReturnValue(evaluated)
+}
+
 func extendFunctionEnv(fn *object.Function, args []object.Object) *object.Environment {
 	env := object.NewEnclosedEnvironment(fn.Env)
 
@@ -169,17 +271,31 @@ func extendFunctionEnv(fn *object.Function, args []object.Object) *object.Enviro
 	return env
 }
 
+// unwrapReturnValue retorna o valor de uma chamada. Um corpo vazio retorna
+// null
 func unwrapReturnValue(obj object.Object) object.Object {
 	if returnValue, ok := obj.(*object.ReturnValue); ok {
-		return returnValue.Value
+		return nullIfNil(returnValue.Value)
 	}
-	return obj
+	return nullIfNil(obj)
 }
 
 func newError(format string, a ...interface{}) *object.Error {
 	return &object.Error{Message: fmt.Sprintf(format, a...)}
 }
 
+// Diagnostic converte um erro de execução em um diagnóstico estruturado
+func Diagnostic(err *object.Error) diag.Diagnostic {
+	d := diag.Errorf(diag.CodeRuntime, diag.PosSpan(err.Pos), "%s", err.Message)
+	if err.Origin.IsValid() {
+		d.Related = append(d.Related, diag.Note{
+			Message: "raised inside the standard library",
+			Span:    diag.PosSpan(err.Origin),
+		})
+	}
+	return d
+}
+
 func isError(obj object.Object) bool {
 	if obj != nil {
 		return obj.Type() == object.ERROR_OBJ
@@ -188,20 +304,211 @@ func isError(obj object.Object) bool {
 }
 
 func evalClassStatement(node *ast.ClassStatement, env *object.Environment) object.Object {
-	class := &object.Class{
-		Name: node.Name.Value,
-		Env:  object.NewEnclosedEnvironment(env),
+	class := NewClass(node.Name.Value, env)
+
+	// Cada base é a superclasse (no máximo uma) ou uma interface
+	for _, base := range node.Bases {
+		value := Eval(base, env)
+		if isError(value) {
+			return value
+		}
+
+		switch value := value.(type) {
+		case *Class:
+			if class.Parent != nil {
+				return newError("class %s cannot extend both %s and %s", class.Name, class.Parent.Name, value.Name)
+			}
+			class.Parent = value
+		case *Interface:
+			class.Interfaces = append(class.Interfaces, value)
+		default:
+			return newError("class %s cannot extend %s: not a class or interface", class.Name, value.Type())
+		}
+	}
+
+	// O corpo da classe só declara propriedades e métodos; nada é executado
+	for _, statement := range node.Body.Statements {
+		switch statement := statement.(type) {
+		case *ast.PropertyStatement:
+			class.AddProperty(statement.Name.Value, statement.Value)
+		case *ast.FunctionStatement:
+			class.AddMethod(statement.Name.Value, &object.Function{
+				Parameters: statement.Parameters,
+				Body:       statement.Body,
+				Env:        env,
+			})
+		default:
+			err := newError("unexpected %s in body of class %s", statement.TokenLiteral(), class.Name)
+			err.Pos = statement.Pos()
+			return err
+		}
+	}
+
+	for _, iface := range class.Interfaces {
+		if err := class.CheckInterface(iface); err != nil {
+			return newError("%s", err)
+		}
 	}
 
 	// Armazena a classe no ambiente
 	env.Set(node.Name.Value, class)
 
-	// Avalia o corpo da classe
-	if node.Body != nil {
-		Eval(node.Body, class.Env)
+	return NULL
+}
+
+func evalInterfaceStatement(node *ast.InterfaceStatement, env *object.Environment) object.Object {
+	iface := NewInterface(node.Name.Value)
+
+	for _, method := range node.Methods {
+		iface.AddMethod(method.Name.Value, len(method.Parameters))
+	}
+
+	env.Set(node.Name.Value, iface)
+
+	return NULL
+}
+
+func evalNewExpression(node *ast.NewExpression, env *object.Environment) object.Object {
+	value := Eval(node.Class, env)
+	if isError(value) {
+		return value
+	}
+
+	class, ok := value.(*Class)
+	if !ok {
+		return newError("cannot instantiate %s: not a class", value.Type())
+	}
+
+	args := evalExpressions(node.Arguments, env)
+	if len(args) == 1 && isError(args[0]) {
+		return args[0]
+	}
+
+	instance := NewInstance(class)
+
+	if err := initProperties(instance, class); err != nil {
+		return err
+	}
+
+	constructor, owner, ok := class.FindMethod("New")
+	if !ok {
+		if len(args) > 0 {
+			return newError("class %s has no constructor New but got %d arguments", class.Name, len(args))
+		}
+		return instance
+	}
+
+	result := applyMethod(instance, owner, "New", constructor, args)
+	if isError(result) {
+		return result
+	}
+
+	return instance
+}
+
+// initProperties atribui os valores padrão das propriedades, começando pela
+// superclasse mais distante. Os valores são avaliados a cada instância para
+// que arrays e hashes não sejam compartilhados entre objetos
+func initProperties(instance *Instance, class *Class) object.Object {
+	if class.Parent != nil {
+		if err := initProperties(instance, class.Parent); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range class.propertyOrder {
+		var value object.Object = NULL
+		if expr := class.Properties[name]; expr != nil {
+			value = Eval(expr, class.Env)
+			if isError(value) {
+				return value
+			}
+		}
+		instance.Set(name, value)
+	}
+
+	return nil
+}
+
+func evalPropertyExpression(node *ast.PropertyExpression, env *object.Environment) object.Object {
+	left := Eval(node.Object, env)
+	if isError(left) {
+		return left
+	}
+	return evalProperty(left, node.Property.Value)
+}
+
+// evalProperty retorna a propriedade ou o método name de left
+func evalProperty(left object.Object, name string) object.Object {
+	switch left := left.(type) {
+	case *Instance:
+		if value, ok := left.Get(name); ok {
+			return value
+		}
+		if method, owner, ok := left.Class.FindMethod(name); ok {
+			return &BoundMethod{Receiver: left, Owner: owner, Method: method, Name: name}
+		}
+		return newError("undefined property %s on %s", name, left.Class.Name)
+	case *object.Module:
+		if value, ok := left.Get(name); ok {
+			return value
+		}
+		if object.IsPrivateName(name) {
+			return newError("%s is private to module %s", name, left.Name)
+		}
+		return newError("module %s has no member %s", left.Name, name)
+	case *Super:
+		if method, owner, ok := left.Class.FindMethod(name); ok {
+			return &BoundMethod{Receiver: left.Receiver, Owner: owner, Method: method, Name: name}
+		}
+		return newError("undefined method %s on superclass %s", name, left.Class.Name)
+	case *object.Hash:
+		if method, ok := hashMethod(left, name); ok {
+			return method
+		}
+		return newError("undefined method %s on HASH", name)
+	case *object.String:
+		if method, ok := stringMethod(left, name); ok {
+			return method
+		}
+		return newError("undefined method %s on STRING", name)
+	default:
+		return newError("property access not supported: %s.%s", left.Type(), name)
+	}
+}
+
+func evalIndexAssignment(target, index, value object.Object) object.Object {
+	switch target := target.(type) {
+	case *object.Hash:
+		key, ok := index.(object.Hashable)
+		if !ok {
+			return newError("unusable as hash key: %s", index.Type())
+		}
+		target.Set(key.HashKey(), object.HashPair{Key: index, Value: value})
+		return value
+	case *object.Array:
+		return evalArrayIndexAssignment(target, index, value)
+	default:
+		return newError("index assignment not supported: %s", target.Type())
 	}
+}
 
-	return class
+// evalSliceBounds avalia os limites de uma fatia; os omitidos ficam nil
+func evalSliceBounds(node *ast.SliceExpression, env *object.Environment) (object.Object, object.Object, object.Object) {
+	var start, end object.Object
+	if node.Start != nil {
+		start = Eval(node.Start, env)
+		if isError(start) {
+			return nil, nil, start
+		}
+	}
+	if node.End != nil {
+		end = Eval(node.End, env)
+		if isError(end) {
+			return nil, nil, end
+		}
+	}
+	return start, end, nil
 }
 
 func evalCallStatement(node *ast.CallStatement, env *object.Environment) object.Object {
@@ -223,6 +530,15 @@ func evalIdentifier(node *ast.Identifier, env *object.Environment) object.Object
 		return builtin
 	}
 
+	// Funções nativas do módulo ao qual o código pertence
+	if name, ok := nativeName(node.Value); ok {
+		if _, file := env.Importer(); file != "" {
+			if fn, ok := lookupNative(moduleName(file), name); ok {
+				return fn
+			}
+		}
+	}
+
 	return newError("identificador não encontrado: %s", node.Value)
 }
 
@@ -232,92 +548,207 @@ func evalBlockStatement(node *ast.BlockStatement, env *object.Environment) objec
 	for _, statement := range node.Statements {
 		result = Eval(statement, env)
 
-		switch result := result.(type) {
-		case *object.ReturnValue:
-			return result.Value
-		case *object.Error:
-			return result
+		// ReturnValue, Break e Continue não são desembrulhados aqui para que
+		// atravessem blocos aninhados até o laço, applyFunction ou evalProgram
+		if result != nil {
+			switch result.Type() {
+			case object.RETURN_VALUE_OBJ, object.ERROR_OBJ, object.BREAK_OBJ, object.CONTINUE_OBJ:
+				return result
+			}
 		}
 	}
 
 	return result
 }
 
-func evalPrefixExpression(operator string, right object.Object) object.Object {
-	switch operator {
-	case "!":
-		return evalBangOperatorExpression(right)
-	case "-":
-		return evalMinusPrefixOperatorExpression(right)
+func evalIfExpression(node *ast.IfExpression, env *object.Environment) object.Object {
+	condition := Eval(node.Condition, env)
+	if isError(condition) {
+		return condition
+	}
+
+	if isTruthy(condition) {
+		return nullIfNil(Eval(node.Consequence, env))
+	} else if node.Alternative != nil {
+		return nullIfNil(Eval(node.Alternative, env))
+	}
+
+	return NULL
+}
+
+// nullIfNil converte a ausência de valor, como a de um bloco vazio, em null
+func nullIfNil(obj object.Object) object.Object {
+	if obj == nil {
+		return NULL
+	}
+	return obj
+}
+
+func evalWhileStatement(node *ast.WhileStatement, env *object.Environment) object.Object {
+	for {
+		condition := Eval(node.Condition, env)
+		if isError(condition) {
+			return condition
+		}
+		if !isTruthy(condition) {
+			return NULL
+		}
+
+		if stop, result := evalLoopBody(node.Body, env); stop {
+			return result
+		}
+	}
+}
+
+func evalForStatement(node *ast.ForStatement, env *object.Environment) object.Object {
+	iterable := Eval(node.Iterable, env)
+	if isError(iterable) {
+		return iterable
+	}
+
+	// iteration associa as variáveis do laço e executa o corpo uma vez
+	iteration := func(key, value object.Object) (bool, object.Object) {
+		if node.Key != nil {
+			env.Set(node.Key.Value, key)
+		}
+		env.Set(node.Value.Value, value)
+		return evalLoopBody(node.Body, env)
+	}
+
+	switch iterable := iterable.(type) {
+	case *object.Array:
+		// O tamanho é fixado no início; elementos alterados pelo corpo do laço
+		// são vistos quando chega a vez deles
+		length := iterable.Len()
+		for i := 0; i < length; i++ {
+			element, ok := iterable.Get(i)
+			if !ok {
+				break
+			}
+			if stop, result := iteration(&object.Integer{Value: int64(i)}, element); stop {
+				return result
+			}
+		}
+	case *object.Hash:
+		for _, pair := range iterable.Ordered() {
+			// Com uma única variável, o laço percorre as chaves
+			value := pair.Key
+			if node.Key != nil {
+				value = pair.Value
+			}
+			if stop, result := iteration(pair.Key, value); stop {
+				return result
+			}
+		}
+	case *object.String:
+		// Strings são percorridas caractere a caractere
+		i := 0
+		for _, r := range iterable.Value {
+			if stop, result := iteration(&object.Integer{Value: int64(i)}, &object.String{Value: string(r)}); stop {
+				return result
+			}
+			i++
+		}
+	case *object.Range:
+		for i := iterable.Start; i < iterable.End; i++ {
+			index := &object.Integer{Value: i - iterable.Start}
+			if stop, result := iteration(index, &object.Integer{Value: i}); stop {
+				return result
+			}
+		}
 	default:
-		return &object.Error{Message: fmt.Sprintf("unknown operator: %s%s", operator, right.Type())}
+		return newError("cannot iterate over %s", iterable.Type())
 	}
+
+	return NULL
 }
 
-func evalBangOperatorExpression(right object.Object) object.Object {
-	switch right {
+// evalLoopBody executa uma iteração e indica se o laço deve terminar. O
+// resultado é NULL para break, ou o ReturnValue/Error que deve subir
+func evalLoopBody(body *ast.BlockStatement, env *object.Environment) (bool, object.Object) {
+	result := Eval(body, env)
+	if result == nil {
+		return false, nil
+	}
+
+	switch result.Type() {
+	case object.BREAK_OBJ:
+		return true, NULL
+	case object.RETURN_VALUE_OBJ, object.ERROR_OBJ:
+		return true, result
+	}
+
+	return false, nil
+}
+
+// isTruthy define a veracidade de um valor: apenas null e false são falsos
+func isTruthy(obj object.Object) bool {
+	switch obj {
+	case NULL, nil:
+		return false
 	case TRUE:
-		return FALSE
+		return true
 	case FALSE:
-		return TRUE
-	case NULL:
-		return TRUE
+		return false
 	default:
-		return FALSE
+		return true
 	}
 }
 
-func evalMinusPrefixOperatorExpression(right object.Object) object.Object {
-	if right.Type() != object.NUMBER_OBJ {
-		return newError("unknown operator: -%s", right.Type())
+func evalPrefixExpression(operator string, right object.Object) object.Object {
+	switch operator {
+	case "!":
+		return evalBangOperatorExpression(right)
+	case "-":
+		return evalMinusPrefixOperatorExpression(right)
+	case "~":
+		return evalBitNotOperatorExpression(right)
+	default:
+		return &object.Error{Message: fmt.Sprintf("unknown operator: %s%s", operator, right.Type())}
 	}
+}
 
-	value := right.(*object.Number).Value
-	return &object.Number{Value: -value}
+func evalBangOperatorExpression(right object.Object) object.Object {
+	return nativeBoolToBooleanObject(!isTruthy(right))
 }
 
 func evalInfixExpression(operator string, left, right object.Object) object.Object {
 	switch {
-	case left.Type() == object.NUMBER_OBJ && right.Type() == object.NUMBER_OBJ:
+	case isNumber(left) && isNumber(right):
 		return evalNumberInfixExpression(operator, left, right)
 	case left.Type() == object.STRING_OBJ && right.Type() == object.STRING_OBJ:
 		return evalStringInfixExpression(operator, left, right)
 	case left.Type() == object.BOOLEAN_OBJ && right.Type() == object.BOOLEAN_OBJ:
 		return evalBooleanInfixExpression(operator, left, right)
 	case left.Type() != right.Type():
-		return newError("type mismatch: %s %s %s", left.Type(), operator, right.Type())
+		return evalMixedInfixExpression(operator, left, right)
+	case operator == "==":
+		// Funções, instâncias e coleções são comparadas por identidade
+		return nativeBoolToBooleanObject(left == right)
+	case operator == "!=":
+		return nativeBoolToBooleanObject(left != right)
 	default:
 		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
 	}
 }
 
-func evalNumberInfixExpression(operator string, left, right object.Object) object.Object {
-	leftVal := left.(*object.Number).Value
-	rightVal := right.(*object.Number).Value
+// evalLogicalExpression avalia && e || em curto-circuito: o lado direito
+// só é avaliado se o esquerdo não decidir o resultado. O resultado é
+// sempre um booleano, segundo as regras de isTruthy
+func evalLogicalExpression(node *ast.InfixExpression, env *object.Environment) object.Object {
+	left := Eval(node.Left, env)
+	if isError(left) {
+		return left
+	}
+	if isTruthy(left) == (node.Operator == "||") {
+		return nativeBoolToBooleanObject(isTruthy(left))
+	}
 
-	switch operator {
-	case "+":
-		return &object.Number{Value: leftVal + rightVal}
-	case "-":
-		return &object.Number{Value: leftVal - rightVal}
-	case "*":
-		return &object.Number{Value: leftVal * rightVal}
-	case "/":
-		if rightVal == 0 {
-			return newError("division by zero")
-		}
-		return &object.Number{Value: leftVal / rightVal}
-	case "<":
-		return nativeBoolToBooleanObject(leftVal < rightVal)
-	case ">":
-		return nativeBoolToBooleanObject(leftVal > rightVal)
-	case "==":
-		return nativeBoolToBooleanObject(leftVal == rightVal)
-	case "!=":
-		return nativeBoolToBooleanObject(leftVal != rightVal)
-	default:
-		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	right := Eval(node.Right, env)
+	if isError(right) {
+		return right
 	}
+	return nativeBoolToBooleanObject(isTruthy(right))
 }
 
 func evalStringInfixExpression(operator string, left, right object.Object) object.Object {
@@ -374,6 +805,10 @@ func evalVarStatement(node *ast.VarStatement, env *object.Environment) object.Ob
 }
 
 func evalReturnStatement(node *ast.ReturnStatement, env *object.Environment) object.Object {
+	if node.ReturnValue == nil {
+		return &object.ReturnValue{Value: NULL}
+	}
+
 	value := Eval(node.ReturnValue, env)
 	if isError(value) {
 		return value
@@ -390,9 +825,9 @@ func nativeBoolToBooleanObject(value bool) object.Object {
 }
 
 func evalHashLiteral(node *ast.HashLiteral, env *object.Environment) object.Object {
-	pairs := make(map[object.HashKey]object.HashPair)
+	hash := object.NewHash()
 
-	for keyNode, valueNode := range node.Pairs {
+	for _, keyNode := range node.Keys {
 		key := Eval(keyNode, env)
 		if isError(key) {
 			return key
@@ -403,41 +838,32 @@ func evalHashLiteral(node *ast.HashLiteral, env *object.Environment) object.Obje
 			return newError("unusable as hash key: %s", key.Type())
 		}
 
-		value := Eval(valueNode, env)
+		value := Eval(node.Pairs[keyNode], env)
 		if isError(value) {
 			return value
 		}
 
-		hashed := hashKey.HashKey()
-		pairs[hashed] = object.HashPair{Key: key, Value: value}
+		hash.Set(hashKey.HashKey(), object.HashPair{Key: key, Value: value})
 	}
 
-	return &object.Hash{Pairs: pairs}
+	return hash
 }
 
 func evalIndexExpression(left, index object.Object) object.Object {
 	switch {
-	case left.Type() == object.ARRAY_OBJ && index.Type() == object.NUMBER_OBJ:
+	case left.Type() == object.ARRAY_OBJ && index.Type() == object.INTEGER_OBJ:
 		return evalArrayIndexExpression(left, index)
+	case left.Type() == object.ARRAY_OBJ:
+		return newError("array index must be INTEGER, got %s", index.Type())
 	case left.Type() == object.HASH_OBJ:
 		return evalHashIndexExpression(left, index)
+	case left.Type() == object.STRING_OBJ:
+		return evalStringIndexExpression(left.(*object.String), index)
 	default:
 		return newError("index operator not supported: %s", left.Type())
 	}
 }
 
-func evalArrayIndexExpression(array, index object.Object) object.Object {
-	arrayObject := array.(*object.Array)
-	idx := int64(index.(*object.Number).Value)
-	max := int64(len(arrayObject.Elements) - 1)
-
-	if idx < 0 || idx > max {
-		return NULL
-	}
-
-	return arrayObject.Elements[idx]
-}
-
 func evalHashIndexExpression(hash, index object.Object) object.Object {
 	hashObject := hash.(*object.Hash)
 
@@ -446,7 +872,7 @@ func evalHashIndexExpression(hash, index object.Object) object.Object {
 		return newError("unusable as hash key: %s", index.Type())
 	}
 
-	pair, ok := hashObject.Pairs[key.HashKey()]
+	pair, ok := hashObject.Get(key.HashKey())
 	if !ok {
 		return NULL
 	}
@@ -0,0 +1,151 @@
+package eval
+
+import (
+	"testing"
+
+	"jotlango/internal/lexer"
+	"jotlango/internal/object"
+	"jotlango/internal/parser"
+)
+
+// testEval avalia input em um ambiente novo; erros de parsing falham o teste
+func testEval(t *testing.T, input string) object.Object {
+	t.Helper()
+	p := parser.NewParser(lexer.NewFileLexer("test.jt", input))
+	program := p.ParseProgram()
+	for _, d := range p.Diagnostics() {
+		t.Fatalf("%q: unexpected diagnostic: %s", input, d)
+	}
+	return Eval(program, object.NewEnvironment())
+}
+
+// expectValue avalia input e compara o resultado formatado com want
+func expectValue(t *testing.T, input string, want string) {
+	t.Helper()
+	result := testEval(t, input)
+	if result == nil {
+		t.Errorf("%q: got nil, want %s", input, want)
+		return
+	}
+	if errObj, ok := result.(*object.Error); ok {
+		t.Errorf("%q: unexpected error: %s", input, errObj.Inspect())
+		return
+	}
+	if got := result.Inspect(); got != want {
+		t.Errorf("%q: got %s, want %s", input, got, want)
+	}
+}
+
+// expectError avalia input e verifica a mensagem e a posição do erro
+func expectError(t *testing.T, input string, message string, line, column int) {
+	t.Helper()
+	errObj, ok := testEval(t, input).(*object.Error)
+	if !ok {
+		t.Errorf("%q: expected an error", input)
+		return
+	}
+	if errObj.Message != message {
+		t.Errorf("%q: message = %q, want %q", input, errObj.Message, message)
+	}
+	if errObj.Pos.Line != line || errObj.Pos.Column != column {
+		t.Errorf("%q: position = %d:%d, want %d:%d", input, errObj.Pos.Line, errObj.Pos.Column, line, column)
+	}
+}
+
+func TestIfElseExpressions(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"if true { 10 }", "10"},
+		{"if false { 10 }", "null"},
+		{"if 1 < 2 { 10 } else { 20 }", "10"},
+		{"if 1 > 2 { 10 } else { 20 }", "20"},
+		{"var x = 5\nif x > 10 { 1 } else if x > 3 { 2 } else { 3 }", "2"},
+		{"var x = 1\nif x > 10 { 1 } else if x > 3 { 2 } else { 3 }", "3"},
+		{"var x = 1\nif x > 10 { 1 } else if x > 3 { 2 }", "null"},
+		{"var r = if true { \"sim\" } else { \"não\" }\nr", "sim"},
+		// Um bloco vazio vale null
+		{"if true {}", "null"},
+		{"if false { 1 } else {}", "null"},
+		{"var x = if true {}\nx", "null"},
+		{"var x = if true {}\nstr(x)", "null"},
+		{"var x = if true {}\n\"${x}\"", "null"},
+	}
+
+	for _, tt := range tests {
+		expectValue(t, tt.input, tt.want)
+	}
+}
+
+func TestTruthiness(t *testing.T) {
+	// Só null e false são falsos; if e ! seguem a mesma regra
+	tests := []struct {
+		value  string
+		truthy bool
+	}{
+		{"true", true},
+		{"false", false},
+		{"null", false},
+		{"0", true},
+		{"0.0", true},
+		{`""`, true},
+		{"[]", true},
+		{"{}", true},
+		{"first([])", false},
+	}
+
+	for _, tt := range tests {
+		want := "false"
+		if tt.truthy {
+			want = "true"
+		}
+		expectValue(t, "if "+tt.value+" { true } else { false }", want)
+		expectValue(t, "!!("+tt.value+")", want)
+	}
+}
+
+func TestIfConditionError(t *testing.T) {
+	expectError(t, "if 1 / 0 { 1 }", "division by zero", 1, 6)
+}
+
+func TestLogicalOperators(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"true && false", "false"},
+		{"true || false", "true"},
+		{"1 < 2 && 2 < 3 || false", "true"},
+		{"!true || true && false", "false"},
+		{"null || 0", "true"},
+		{"\"a\" && null", "false"},
+		// O lado direito não é avaliado quando o esquerdo decide o resultado
+		{"false && 1 / 0", "false"},
+		{"true || 1 / 0", "true"},
+		{"var calls = 0\nfn touch() {\n  calls = calls + 1\n  return true\n}\nfalse && touch()\ntrue || touch()\ntrue && touch()\nfalse || touch()\ncalls", "2"},
+	}
+
+	for _, tt := range tests {
+		expectValue(t, tt.input, tt.want)
+	}
+
+	expectError(t, "true && 1 / 0", "division by zero", 1, 11)
+}
+
+func TestReturn(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"fn f() { return 1\n2 }\nf()", "1"},
+		{"fn f() { return }\nf()", "null"},
+		{"fn f() {\n  return\n}\nf()", "null"},
+		{"var log = []\nfn f(x) {\n  if x < 0 { return }\n  log = push(log, x)\n}\nf(-1)\nf(2)\nlog", "[2]"},
+		{"fn f() {\n  for i in 0..10 {\n    if i == 3 { return i }\n  }\n}\nf()", "3"},
+	}
+
+	for _, tt := range tests {
+		expectValue(t, tt.input, tt.want)
+	}
+}
@@ -0,0 +1,96 @@
+package eval
+
+import (
+	"jotlango/internal/object"
+)
+
+// hashMethods são os métodos chamados com h.nome(args)
+var hashMethods = map[string]func(h *object.Hash, args ...object.Object) object.Object{
+	// keys retorna as chaves na ordem de inserção
+	"keys": func(h *object.Hash, args ...object.Object) object.Object {
+		if len(args) != 0 {
+			return newError("wrong number of arguments to keys. got=%d, want=0", len(args))
+		}
+		pairs := h.Ordered()
+		keys := make([]object.Object, len(pairs))
+		for i, pair := range pairs {
+			keys[i] = pair.Key
+		}
+		return &object.Array{Elements: keys}
+	},
+	// values retorna os valores na ordem de inserção
+	"values": func(h *object.Hash, args ...object.Object) object.Object {
+		if len(args) != 0 {
+			return newError("wrong number of arguments to values. got=%d, want=0", len(args))
+		}
+		pairs := h.Ordered()
+		values := make([]object.Object, len(pairs))
+		for i, pair := range pairs {
+			values[i] = pair.Value
+		}
+		return &object.Array{Elements: values}
+	},
+	// entries retorna um array de pares [chave, valor]
+	"entries": func(h *object.Hash, args ...object.Object) object.Object {
+		if len(args) != 0 {
+			return newError("wrong number of arguments to entries. got=%d, want=0", len(args))
+		}
+		pairs := h.Ordered()
+		entries := make([]object.Object, len(pairs))
+		for i, pair := range pairs {
+			entries[i] = &object.Array{Elements: []object.Object{pair.Key, pair.Value}}
+		}
+		return &object.Array{Elements: entries}
+	},
+	"has": func(h *object.Hash, args ...object.Object) object.Object {
+		if len(args) != 1 {
+			return newError("wrong number of arguments to has. got=%d, want=1", len(args))
+		}
+		key, ok := args[0].(object.Hashable)
+		if !ok {
+			return FALSE
+		}
+		_, found := h.Get(key.HashKey())
+		return nativeBoolToBooleanObject(found)
+	},
+	// delete remove a chave e retorna se ela existia
+	"delete": func(h *object.Hash, args ...object.Object) object.Object {
+		if len(args) != 1 {
+			return newError("wrong number of arguments to delete. got=%d, want=1", len(args))
+		}
+		key, ok := args[0].(object.Hashable)
+		if !ok {
+			return newError("unusable as hash key: %s", args[0].Type())
+		}
+		return nativeBoolToBooleanObject(h.Delete(key.HashKey()))
+	},
+	// merge retorna um novo hash com os pares de h seguidos dos de other;
+	// em chaves repetidas prevalece other. Nenhum dos dois é alterado
+	"merge": func(h *object.Hash, args ...object.Object) object.Object {
+		if len(args) != 1 {
+			return newError("wrong number of arguments to merge. got=%d, want=1", len(args))
+		}
+		other, ok := args[0].(*object.Hash)
+		if !ok {
+			return newError("argument to merge must be HASH, got %s", args[0].Type())
+		}
+		merged := object.NewHash()
+		for _, source := range []*object.Hash{h, other} {
+			for _, pair := range source.Ordered() {
+				merged.Set(pair.Key.(object.Hashable).HashKey(), pair)
+			}
+		}
+		return merged
+	},
+}
+
+// hashMethod retorna o método name associado a h
+func hashMethod(h *object.Hash, name string) (*object.Builtin, bool) {
+	method, ok := hashMethods[name]
+	if !ok {
+		return nil, false
+	}
+	return &object.Builtin{Fn: func(args ...object.Object) object.Object {
+		return method(h, args...)
+	}}, true
+}
@@ -0,0 +1,60 @@
+package eval
+
+import "testing"
+
+func TestHashOrderAndAssignment(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`{"b": 1, "a": 2, "c": 3}`, "{b: 1, a: 2, c: 3}"},
+		// Uma chave nova vai para o fim; uma existente mantém a posição
+		{"var h = {\"b\": 1, \"a\": 2}\nh[\"c\"] = 3\nh[\"b\"] = 10\nh", "{b: 10, a: 2, c: 3}"},
+		{"var h = {\"a\": 1}\nh[\"a\"] += 5\nh", "{a: 6}"},
+		{"var h = {}\nh[1] = \"um\"\nh[true] = \"sim\"\nh", "{1: um, true: sim}"},
+		// 1 e 1.0 são a mesma chave, assim como 1 == 1.0
+		{"var h = {1: \"a\"}\nh[1.0]", "a"},
+		{`{"a": 1}["b"]`, "null"},
+		{"len({\"a\": 1, \"b\": 2})", "2"},
+		{"len({})", "0"},
+	}
+
+	for _, tt := range tests {
+		expectValue(t, tt.input, tt.want)
+	}
+
+	expectError(t, "var h = {}\nh[[1]] = 2", "unusable as hash key: ARRAY", 2, 8)
+	expectError(t, "{[1]: 2}", "unusable as hash key: ARRAY", 1, 1)
+}
+
+func TestHashMethods(t *testing.T) {
+	const h = "var h = {\"b\": 1, \"a\": 2}\n"
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"h.keys()", "[b, a]"},
+		{"h.values()", "[1, 2]"},
+		{"h.entries()", "[[b, 1], [a, 2]]"},
+		{"{}.keys()", "[]"},
+		{`h.has("a")`, "true"},
+		{`h.has("z")`, "false"},
+		{"h.has([1])", "false"},
+		// delete retorna se a chave existia e tira a chave da ordem
+		{"h.delete(\"b\")\nh", "{a: 2}"},
+		{"var r = [h.delete(\"b\"), h.delete(\"b\")]\nr", "[true, false]"},
+		{"h.delete(\"b\")\nh[\"b\"] = 3\nh", "{a: 2, b: 3}"},
+		// merge retorna um novo hash; os valores do argumento prevalecem
+		{`h.merge({"a": 0, "c": 4})`, "{b: 1, a: 0, c: 4}"},
+		{"var m = h.merge({\"c\": 4})\nh", "{b: 1, a: 2}"},
+		{"var out = []\nfor k, v in h.merge({\"c\": 3}) { out = push(out, k) }\nout", "[b, a, c]"},
+	}
+
+	for _, tt := range tests {
+		expectValue(t, h+tt.input, tt.want)
+	}
+
+	expectError(t, h+"h.keys(1)", "wrong number of arguments to keys. got=1, want=0", 2, 7)
+	expectError(t, h+"h.merge(1)", "argument to merge must be HASH, got INTEGER", 2, 8)
+	expectError(t, h+"h.nope()", "undefined method nope on HASH", 2, 2)
+}
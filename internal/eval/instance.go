@@ -4,18 +4,25 @@ import (
 	"bytes"
 	"fmt"
 	"jotlango/internal/object"
+	"sync"
 )
 
+// Instance é um objeto criado a partir de uma classe. As propriedades só são
+// acessadas pelos métodos, que podem ser chamados de várias goroutines
 type Instance struct {
-	Class      *Class
-	Properties map[string]object.Object
+	Class *Class
+
+	mu         sync.RWMutex
+	properties map[string]object.Object
+	order      []string
 }
 
-func (i *Instance) Type() object.ObjectType { return "INSTANCE" }
+func (i *Instance) Type() object.ObjectType { return object.INSTANCE_OBJ }
 func (i *Instance) Inspect() string {
 	var out bytes.Buffer
 	out.WriteString(i.Class.Name + " {")
-	for name, value := range i.Properties {
+	for _, name := range i.PropertyNames() {
+		value, _ := i.Get(name)
 		out.WriteString(fmt.Sprintf("\n  %s: %s", name, value.Inspect()))
 	}
 	out.WriteString("\n}")
@@ -23,15 +30,35 @@ func (i *Instance) Inspect() string {
 }
 
 func NewInstance(class *Class) *Instance {
-	instance := &Instance{
+	return &Instance{
 		Class:      class,
-		Properties: make(map[string]object.Object),
+		properties: make(map[string]object.Object),
 	}
+}
 
-	// Copiar propriedades da classe para a instância
-	for name, value := range class.Properties {
-		instance.Properties[name] = value
+// Get retorna o valor de uma propriedade
+func (i *Instance) Get(name string) (object.Object, bool) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	value, ok := i.properties[name]
+	return value, ok
+}
+
+// Set define o valor de uma propriedade, declarada ou não
+func (i *Instance) Set(name string, value object.Object) object.Object {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if _, ok := i.properties[name]; !ok {
+		i.order = append(i.order, name)
 	}
+	i.properties[name] = value
+	return value
+}
 
-	return instance
+// PropertyNames retorna os nomes das propriedades na ordem em que foram
+// definidas
+func (i *Instance) PropertyNames() []string {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return append([]string{}, i.order...)
 }
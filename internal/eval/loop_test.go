@@ -0,0 +1,85 @@
+package eval
+
+import "testing"
+
+func TestWhile(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"var i = 0\nwhile i < 5 { i++ }\ni", "5"},
+		{"var i = 0\nwhile false { i++ }\ni", "0"},
+		{"var i = 0\nwhile true {\n    i++\n    if i == 3 { break }\n}\ni", "3"},
+		// Soma os ímpares até 7: continue pula os pares, break encerra
+		{"var s = 0\nvar i = 0\nwhile i < 10 {\n    i++\n    if i % 2 == 0 { continue }\n    if i > 7 { break }\n    s += i\n}\ns", "16"},
+	}
+
+	for _, tt := range tests {
+		expectValue(t, tt.input, tt.want)
+	}
+}
+
+func TestForIn(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"var out = []\nfor x in [1, 2, 3] { out = push(out, x * 2) }\nout", "[2, 4, 6]"},
+		{"var out = []\nfor i, x in [\"a\", \"b\"] { out = push(out, \"${i}${x}\") }\nout", "[0a, 1b]"},
+		{"var n = 0\nfor x in [] { n++ }\nn", "0"},
+
+		// Hashes: uma variável percorre as chaves, duas recebem chave e valor,
+		// na ordem de inserção
+		{"var out = []\nfor k in {\"b\": 1, \"a\": 2} { out = push(out, k) }\nout", "[b, a]"},
+		{"var out = []\nfor k, v in {\"b\": 1, \"a\": 2} { out = push(out, k + \"=\" + str(v)) }\nout", "[b=1, a=2]"},
+
+		// Intervalos são semiabertos; um intervalo invertido é vazio
+		{"var out = []\nfor n in 0..3 { out = push(out, n) }\nout", "[0, 1, 2]"},
+		{"var n = 0\nfor x in 3..0 { n++ }\nn", "0"},
+		{"var out = []\nfor i, n in 5..7 { out = push(out, i * 10 + n) }\nout", "[5, 16]"},
+
+		// Strings são percorridas por caractere
+		{"var out = []\nfor c in \"aé😀\" { out = push(out, c) }\nout", "[a, é, 😀]"},
+
+		// Um elemento alterado pelo corpo é visto quando chega a vez dele
+		{"var arr = [1, 2, 3]\nvar out = []\nfor i, x in arr {\n    if i == 0 { arr[2] = 30 }\n    out = push(out, x)\n}\nout", "[1, 2, 30]"},
+	}
+
+	for _, tt := range tests {
+		expectValue(t, tt.input, tt.want)
+	}
+}
+
+func TestBreakAndContinue(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"var out = []\nfor x in 0..10 {\n    if x == 3 { break }\n    out = push(out, x)\n}\nout", "[0, 1, 2]"},
+		{"var out = []\nfor x in 0..6 {\n    if x % 2 == 1 { continue }\n    out = push(out, x)\n}\nout", "[0, 2, 4]"},
+		{"var out = []\nfor k, v in {\"a\": 1, \"b\": 2, \"c\": 3} {\n    if v == 2 { continue }\n    out = push(out, k)\n}\nout", "[a, c]"},
+
+		// break e continue afetam apenas o laço mais interno
+		{"var t = 0\nfor a in [1, 2] {\n    for b in [10, 20, 30] {\n        if b == 20 { break }\n        t += a * b\n    }\n}\nt", "30"},
+		{"var t = 0\nfor a in [1, 2] {\n    for b in [10, 20] {\n        if b == 10 { continue }\n        t += a * b\n    }\n}\nt", "60"},
+
+		// Dentro de blocos aninhados o sinal sobe até o laço
+		{"var i = 0\nwhile true {\n    i++\n    if i > 1 {\n        if i == 4 { break }\n    }\n}\ni", "4"},
+	}
+
+	for _, tt := range tests {
+		expectValue(t, tt.input, tt.want)
+	}
+}
+
+// return dentro de um laço sai da função, não só do laço
+func TestReturnInsideLoop(t *testing.T) {
+	expectValue(t, "fn f() {\n    for x in [1, 2, 3] {\n        if x == 2 { return x * 10 }\n    }\n    99\n}\nf()", "20")
+	expectValue(t, "fn f() {\n    while true {\n        for x in 0..5 { return x }\n    }\n}\nf()", "0")
+}
+
+func TestLoopErrors(t *testing.T) {
+	expectError(t, "for x in 5 { }", "cannot iterate over INTEGER", 1, 1)
+	expectError(t, "for x in [1, 0] { 1 / x }", "division by zero", 1, 21)
+	expectError(t, "var i = 0\nwhile i < 3 {\n    i++\n    if i == 2 { undefinedName }\n}", "identificador não encontrado: undefinedName", 4, 17)
+}
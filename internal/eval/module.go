@@ -0,0 +1,299 @@
+package eval
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"jotlango/internal/ast"
+	"jotlango/internal/lexer"
+	"jotlango/internal/object"
+	"jotlango/internal/parser"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ProjectFile é o nome do arquivo de projeto que mapeia módulos a caminhos
+const ProjectFile = "jot.json"
+
+// Project representa o conteúdo de jot.json
+type Project struct {
+	Name         string            `json:"name"`
+	Version      string            `json:"version"`
+	Main         string            `json:"main"`
+	Dependencies map[string]string `json:"dependencies"`
+
+	Dir string `json:"-"` // diretório onde jot.json foi encontrado
+}
+
+// FindProject procura jot.json em dir e nos diretórios acima. Retorna nil,
+// sem erro, quando não há arquivo de projeto
+func FindProject(dir string) (*Project, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		path := filepath.Join(dir, ProjectFile)
+		content, err := os.ReadFile(path)
+		if err == nil {
+			project := &Project{Dir: dir}
+			if err := json.Unmarshal(content, project); err != nil {
+				return nil, fmt.Errorf("%s: %v", path, err)
+			}
+			return project, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+// stdlibPrefix identifica, no cache e nas posições, arquivos lidos da
+// biblioteca padrão embutida
+const stdlibPrefix = "stdlib:"
+
+var stdlib fs.FS
+
+// inStdlib indica se pos está em um arquivo da biblioteca padrão embutida
+func inStdlib(pos lexer.Position) bool {
+	return strings.HasPrefix(pos.File, stdlibPrefix)
+}
+
+// SetStdlib define onde estão os módulos da biblioteca padrão, como
+// math/math.jt. Eles são usados quando um import não está nas dependencies
+// do projeto
+func SetStdlib(fsys fs.FS) {
+	stdlib = fsys
+}
+
+// Loader resolve imports, avalia cada módulo em seu próprio ambiente e
+// mantém um cache para que cada arquivo seja carregado uma única vez
+type Loader struct {
+	project *Project
+
+	// mu protege cache e loading, já que handlers HTTP podem importar
+	// módulos ao mesmo tempo. A pilha é uma só, então um mesmo arquivo
+	// importado por duas goroutines ao mesmo tempo é reportado como ciclo
+	mu      sync.Mutex
+	cache   map[string]*object.Module
+	loading []string // pilha de arquivos sendo carregados, para detectar ciclos
+}
+
+func NewLoader(project *Project) *Loader {
+	return &Loader{
+		project: project,
+		cache:   make(map[string]*object.Module),
+	}
+}
+
+// Import implementa object.Importer
+func (l *Loader) Import(path string, from string) object.Object {
+	file, err := l.resolve(path, from)
+	l.mu.Lock()
+	if err != nil {
+		defer l.mu.Unlock()
+		// Módulos registrados apenas em Go não precisam de arquivo .jt
+		if module, ok := l.cache["native:"+path]; ok {
+			return module
+		}
+		if module, ok := nativeModule(path); ok {
+			l.cache[module.Path] = module
+			return module
+		}
+		return newError("%s", err)
+	}
+
+	if module, ok := l.cache[file]; ok {
+		l.mu.Unlock()
+		return module
+	}
+
+	for i, loading := range l.loading {
+		if loading == file {
+			cycle := append(append([]string{}, l.loading[i:]...), file)
+			l.mu.Unlock()
+			for j := range cycle {
+				cycle[j] = filepath.Base(cycle[j])
+			}
+			return newError("import cycle: %s", strings.Join(cycle, " -> "))
+		}
+	}
+	l.mu.Unlock()
+
+	content, err := readModule(file)
+	if err != nil {
+		return newError("cannot read module %q: %v", path, err)
+	}
+
+	p := parser.NewParser(lexer.NewFileLexer(file, string(content)))
+	program := p.ParseProgram()
+	if errors := p.Errors(); len(errors) > 0 {
+		return newError("cannot parse module %q: %s", path, strings.Join(errors, "; "))
+	}
+
+	l.enter(file)
+	defer l.leave(file)
+
+	env := object.NewModuleEnvironment(file, l)
+	if result := Eval(program, env); isError(result) {
+		return result
+	}
+
+	module := &object.Module{Name: moduleName(path), Path: file, Env: env}
+	l.mu.Lock()
+	l.cache[file] = module
+	l.mu.Unlock()
+
+	return module
+}
+
+// enter marca file como em carregamento; usado para o arquivo principal, que
+// não passa por Import
+func (l *Loader) enter(file string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.loading = append(l.loading, file)
+}
+
+// leave desfaz enter
+func (l *Loader) leave(file string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i := len(l.loading) - 1; i >= 0; i-- {
+		if l.loading[i] == file {
+			l.loading = append(l.loading[:i], l.loading[i+1:]...)
+			return
+		}
+	}
+}
+
+// resolve converte o caminho de um import em um arquivo .jt absoluto.
+// Caminhos iniciados por ./ ou ../ são relativos ao arquivo que importa;
+// os demais são procurados nas dependencies de jot.json, aceitando
+// submódulos como "modulo/submodulo"
+func (l *Loader) resolve(path string, from string) (string, error) {
+	var target string
+
+	if strings.HasPrefix(path, "./") || strings.HasPrefix(path, "../") {
+		if strings.HasPrefix(from, stdlibPrefix) {
+			if file, ok := resolveStdlibRelative(path, from); ok {
+				return file, nil
+			}
+			return "", fmt.Errorf("module not found: %s", path)
+		}
+
+		base := "."
+		if from != "" {
+			base = filepath.Dir(from)
+		}
+		target = filepath.Join(base, path)
+	} else {
+		name, sub, _ := strings.Cut(path, "/")
+		var dep string
+		if l.project != nil {
+			dep = l.project.Dependencies[name]
+		}
+		if dep == "" {
+			if file, ok := resolveStdlib(path); ok {
+				return file, nil
+			}
+			if l.project == nil {
+				return "", fmt.Errorf("module not found: %s (no %s)", path, ProjectFile)
+			}
+			return "", fmt.Errorf("module not found: %s (not in %s dependencies)", path, ProjectFile)
+		}
+		// Caminhos relativos nas dependencies partem do diretório de jot.json
+		if !filepath.IsAbs(dep) {
+			dep = filepath.Join(l.project.Dir, dep)
+		}
+		target = filepath.Join(dep, sub)
+	}
+
+	target, err := filepath.Abs(target)
+	if err != nil {
+		return "", err
+	}
+
+	// Um diretório é carregado pelo arquivo de mesmo nome: stdlib/io/io.jt
+	if info, err := os.Stat(target); err == nil && info.IsDir() {
+		target = filepath.Join(target, filepath.Base(target)+".jt")
+	} else if filepath.Ext(target) == "" {
+		target += ".jt"
+	}
+
+	if _, err := os.Stat(target); err != nil {
+		return "", fmt.Errorf("module not found: %s (%s does not exist)", path, target)
+	}
+
+	return target, nil
+}
+
+// resolveStdlib procura name na biblioteca padrão, com as mesmas regras de
+// resolve: um diretório é carregado pelo arquivo de mesmo nome
+func resolveStdlib(name string) (string, bool) {
+	if stdlib == nil || !fs.ValidPath(name) {
+		return "", false
+	}
+
+	target := name
+	if info, err := fs.Stat(stdlib, target); err == nil && info.IsDir() {
+		target = path.Join(target, path.Base(target)+".jt")
+	} else if path.Ext(target) == "" {
+		target += ".jt"
+	}
+
+	if info, err := fs.Stat(stdlib, target); err != nil || info.IsDir() {
+		return "", false
+	}
+
+	return stdlibPrefix + target, true
+}
+
+// resolveStdlibRelative resolve um import relativo feito por um módulo da
+// biblioteca padrão
+func resolveStdlibRelative(rel string, from string) (string, bool) {
+	dir := path.Dir(strings.TrimPrefix(from, stdlibPrefix))
+	return resolveStdlib(path.Join(dir, rel))
+}
+
+// readModule lê o conteúdo de um arquivo resolvido, do disco ou da
+// biblioteca padrão
+func readModule(file string) ([]byte, error) {
+	if name, ok := strings.CutPrefix(file, stdlibPrefix); ok {
+		return fs.ReadFile(stdlib, name)
+	}
+	return os.ReadFile(file)
+}
+
+// moduleName é o nome local padrão de um import: o último elemento do
+// caminho sem extensão
+func moduleName(path string) string {
+	return strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+}
+
+func evalImportStatement(node *ast.ImportStatement, env *object.Environment) object.Object {
+	importer, from := env.Importer()
+	if importer == nil {
+		return newError("import not available: no module loader")
+	}
+
+	module := importer.Import(node.Path, from)
+	if isError(module) {
+		return module
+	}
+
+	name := moduleName(node.Path)
+	if node.Alias != nil {
+		name = node.Alias.Value
+	}
+	env.Set(name, module)
+
+	return NULL
+}
@@ -0,0 +1,57 @@
+package eval
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResolveDependencies(t *testing.T) {
+	dir := t.TempDir()
+	project := filepath.Join(dir, "app")
+	shared := filepath.Join(dir, "shared")
+
+	writeFile(t, filepath.Join(project, "libs", "util", "util.jt"), "")
+	writeFile(t, filepath.Join(project, "libs", "util", "text.jt"), "")
+	writeFile(t, filepath.Join(shared, "shared.jt"), "")
+	writeFile(t, filepath.Join(shared, "db", "db.jt"), "")
+
+	l := NewLoader(&Project{
+		Dir: project,
+		Dependencies: map[string]string{
+			"util":   "./libs/util",
+			"shared": shared,
+		},
+	})
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"util", filepath.Join(project, "libs", "util", "util.jt")},
+		{"util/text", filepath.Join(project, "libs", "util", "text.jt")},
+		{"shared", filepath.Join(shared, "shared.jt")},
+		{"shared/db", filepath.Join(shared, "db", "db.jt")},
+	}
+
+	for _, tt := range tests {
+		got, err := l.resolve(tt.path, filepath.Join(project, "main.jt"))
+		if err != nil {
+			t.Errorf("%s: %v", tt.path, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,378 @@
+package eval
+
+import (
+	"fmt"
+	"jotlango/internal/object"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// nativePrefix é o prefixo com que um módulo .jt acessa as funções nativas
+// registradas com o seu nome: em stdlib/math/math.jt, __native_sqrt
+const nativePrefix = "__native_"
+
+var (
+	nativesMu sync.RWMutex
+	natives   = map[string]map[string]*object.Builtin{}
+)
+
+// RegisterNative registra uma função Go no módulo informado. Ela fica
+// disponível como __native_<name> dentro do módulo .jt de mesmo nome e, se o
+// módulo não tiver um arquivo .jt, diretamente via import "<module>"
+func RegisterNative(module, name string, fn object.BuiltinFunction) {
+	nativesMu.Lock()
+	defer nativesMu.Unlock()
+
+	if natives[module] == nil {
+		natives[module] = map[string]*object.Builtin{}
+	}
+	natives[module][name] = &object.Builtin{Fn: fn}
+}
+
+// RegisterNatives registra várias funções Go comuns de uma vez, convertendo
+// argumentos e retornos com WrapNative
+func RegisterNatives(module string, fns map[string]interface{}) {
+	for name, fn := range fns {
+		RegisterNative(module, name, WrapNative(name, fn))
+	}
+}
+
+// lookupNative procura uma função nativa registrada
+func lookupNative(module, name string) (*object.Builtin, bool) {
+	nativesMu.RLock()
+	defer nativesMu.RUnlock()
+
+	fn, ok := natives[module][name]
+	return fn, ok
+}
+
+// nativeModule cria um módulo com todas as funções nativas de name, para
+// imports que não têm um arquivo .jt correspondente
+func nativeModule(name string) (*object.Module, bool) {
+	nativesMu.RLock()
+	defer nativesMu.RUnlock()
+
+	fns, ok := natives[name]
+	if !ok {
+		return nil, false
+	}
+
+	env := object.NewEnvironment()
+	for fnName, fn := range fns {
+		env.Set(fnName, fn)
+	}
+
+	return &object.Module{Name: name, Path: "native:" + name, Env: env}, true
+}
+
+// Call chama uma função JotLang, um método ou um builtin a partir de Go;
+// usado por funções nativas que recebem callbacks
+func Call(fn object.Object, args ...object.Object) object.Object {
+	return applyFunction(fn, args)
+}
+
+var (
+	objectType = reflect.TypeOf((*object.Object)(nil)).Elem()
+	errorType  = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// WrapNative adapta uma função Go comum, como math.Sqrt ou
+// func(string, int) (string, error), a uma BuiltinFunction. Os argumentos
+// são convertidos de object.Object para os tipos dos parâmetros e o retorno
+// com FromGo; um error não-nil como último retorno vira *object.Error.
+// Entra em pânico se fn não for uma função, pois isso é um erro de
+// programação de quem registra
+func WrapNative(name string, fn interface{}) object.BuiltinFunction {
+	value := reflect.ValueOf(fn)
+	if value.Kind() != reflect.Func {
+		panic(fmt.Sprintf("WrapNative(%s): expected a function, got %T", name, fn))
+	}
+
+	fnType := value.Type()
+	numIn := fnType.NumIn()
+
+	return func(args ...object.Object) object.Object {
+		if fnType.IsVariadic() {
+			if len(args) < numIn-1 {
+				return newError("wrong number of arguments to `%s`. got=%d, want at least %d", name, len(args), numIn-1)
+			}
+		} else if len(args) != numIn {
+			return newError("wrong number of arguments to `%s`. got=%d, want=%d", name, len(args), numIn)
+		}
+
+		in := make([]reflect.Value, len(args))
+		for i, arg := range args {
+			paramType := nativeParamType(fnType, i)
+			converted, err := ConvertArg(arg, paramType)
+			if err != nil {
+				return newError("argument %d to `%s`: %s", i+1, name, err)
+			}
+			in[i] = converted
+		}
+
+		return nativeResult(name, value.Call(in))
+	}
+}
+
+// nativeParamType retorna o tipo esperado do i-ésimo argumento, considerando
+// funções variádicas
+func nativeParamType(fnType reflect.Type, i int) reflect.Type {
+	if fnType.IsVariadic() && i >= fnType.NumIn()-1 {
+		return fnType.In(fnType.NumIn() - 1).Elem()
+	}
+	return fnType.In(i)
+}
+
+// nativeResult converte os retornos de uma função Go em um único objeto
+func nativeResult(name string, out []reflect.Value) object.Object {
+	if len(out) > 0 && out[len(out)-1].Type() == errorType {
+		if err, _ := out[len(out)-1].Interface().(error); err != nil {
+			return newError("%s: %s", name, err)
+		}
+		out = out[:len(out)-1]
+	}
+
+	switch len(out) {
+	case 0:
+		return NULL
+	case 1:
+		return FromGo(out[0].Interface())
+	default:
+		elements := make([]object.Object, len(out))
+		for i, v := range out {
+			elements[i] = FromGo(v.Interface())
+		}
+		return &object.Array{Elements: elements}
+	}
+}
+
+// ConvertArg converte um objeto para um valor Go do tipo t
+func ConvertArg(obj object.Object, t reflect.Type) (reflect.Value, error) {
+	if t.Implements(objectType) && reflect.TypeOf(obj).AssignableTo(t) {
+		return reflect.ValueOf(obj), nil
+	}
+
+	// null é aceito onde Go aceita nil
+	if _, ok := obj.(*object.Null); ok {
+		switch t.Kind() {
+		case reflect.Map, reflect.Slice, reflect.Ptr:
+			return reflect.Zero(t), nil
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+		if t.NumMethod() == 0 {
+			goValue := ToGo(obj)
+			if goValue == nil {
+				return reflect.Zero(t), nil
+			}
+			return reflect.ValueOf(goValue), nil
+		}
+	case reflect.Float64, reflect.Float32:
+		// Inteiros são aceitos onde Go espera float
+		if isNumber(obj) {
+			return reflect.ValueOf(toFloat(obj)).Convert(t), nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		switch n := obj.(type) {
+		case *object.Integer:
+			return convertInt(n.Value, t)
+		case *object.Float:
+			// Floats só são aceitos com valor inteiro, como 2.0
+			if n.Value != math.Trunc(n.Value) || n.Value < math.MinInt64 || n.Value >= math.MaxInt64 {
+				return reflect.Value{}, fmt.Errorf("expected integer, got %s", n.Inspect())
+			}
+			return convertInt(int64(n.Value), t)
+		}
+	case reflect.String:
+		if s, ok := obj.(*object.String); ok {
+			return reflect.ValueOf(s.Value).Convert(t), nil
+		}
+	case reflect.Bool:
+		if b, ok := obj.(*object.Boolean); ok {
+			return reflect.ValueOf(b.Value).Convert(t), nil
+		}
+	case reflect.Slice:
+		if arr, ok := obj.(*object.Array); ok {
+			elements := arr.Snapshot()
+			slice := reflect.MakeSlice(t, len(elements), len(elements))
+			for i, el := range elements {
+				v, err := ConvertArg(el, t.Elem())
+				if err != nil {
+					return reflect.Value{}, fmt.Errorf("element %d: %s", i, err)
+				}
+				slice.Index(i).Set(v)
+			}
+			return slice, nil
+		}
+	case reflect.Map:
+		if hash, ok := obj.(*object.Hash); ok && t.Key().Kind() == reflect.String {
+			pairs := hash.Ordered()
+			m := reflect.MakeMapWithSize(t, len(pairs))
+			for _, pair := range pairs {
+				key, ok := pair.Key.(*object.String)
+				if !ok {
+					return reflect.Value{}, fmt.Errorf("expected STRING keys, got %s", pair.Key.Type())
+				}
+				v, err := ConvertArg(pair.Value, t.Elem())
+				if err != nil {
+					return reflect.Value{}, fmt.Errorf("key %q: %s", key.Value, err)
+				}
+				m.SetMapIndex(reflect.ValueOf(key.Value).Convert(t.Key()), v)
+			}
+			return m, nil
+		}
+	}
+
+	return reflect.Value{}, fmt.Errorf("expected %s, got %s", goTypeName(t), obj.Type())
+}
+
+// convertInt converte n para o tipo inteiro t, com erro se n não couber:
+// 300 não vira 44 em um uint8, nem -1 vira um uint enorme
+func convertInt(n int64, t reflect.Type) (reflect.Value, error) {
+	v := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n < 0 || v.OverflowUint(uint64(n)) {
+			return reflect.Value{}, fmt.Errorf("integer %d out of range for %s", n, t)
+		}
+		v.SetUint(uint64(n))
+	default:
+		if v.OverflowInt(n) {
+			return reflect.Value{}, fmt.Errorf("integer %d out of range for %s", n, t)
+		}
+		v.SetInt(n)
+	}
+	return v, nil
+}
+
+// goTypeName descreve um tipo Go com o nome do tipo de objeto equivalente
+func goTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Float64, reflect.Float32:
+		return object.FLOAT_OBJ
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return object.INTEGER_OBJ
+	case reflect.String:
+		return object.STRING_OBJ
+	case reflect.Bool:
+		return object.BOOLEAN_OBJ
+	case reflect.Slice:
+		return object.ARRAY_OBJ
+	case reflect.Map:
+		return object.HASH_OBJ
+	}
+	return t.String()
+}
+
+// ToGo converte um objeto em um valor Go: int64, float64, string, bool, nil,
+// []interface{} ou map[string]interface{}. Outros objetos são retornados
+// sem conversão
+func ToGo(obj object.Object) interface{} {
+	switch obj := obj.(type) {
+	case *object.Integer:
+		return obj.Value
+	case *object.Float:
+		return obj.Value
+	case *object.String:
+		return obj.Value
+	case *object.Boolean:
+		return obj.Value
+	case *object.Null:
+		return nil
+	case *object.Array:
+		elements := obj.Snapshot()
+		values := make([]interface{}, len(elements))
+		for i, el := range elements {
+			values[i] = ToGo(el)
+		}
+		return values
+	case *object.Hash:
+		pairs := obj.Ordered()
+		values := make(map[string]interface{}, len(pairs))
+		for _, pair := range pairs {
+			key := pair.Key.Inspect()
+			values[key] = ToGo(pair.Value)
+		}
+		return values
+	default:
+		return obj
+	}
+}
+
+// FromGo converte um valor Go em objeto. Inteiros viram Integer, floats
+// viram Float, slices viram Array e mapas viram Hash com chaves em ordem
+// alfabética; um error vira *object.Error
+func FromGo(v interface{}) object.Object {
+	switch v := v.(type) {
+	case nil:
+		return NULL
+	case object.Object:
+		return v
+	case error:
+		return newError("%s", v)
+	case bool:
+		return nativeBoolToBooleanObject(v)
+	case string:
+		return &object.String{Value: v}
+	}
+
+	value := reflect.ValueOf(v)
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &object.Integer{Value: value.Int()}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if value.Uint() > math.MaxInt64 {
+			return &object.Float{Value: float64(value.Uint())}
+		}
+		return &object.Integer{Value: int64(value.Uint())}
+	case reflect.Float32, reflect.Float64:
+		return &object.Float{Value: value.Float()}
+	case reflect.String:
+		return &object.String{Value: value.String()}
+	case reflect.Bool:
+		return nativeBoolToBooleanObject(value.Bool())
+	case reflect.Slice, reflect.Array:
+		elements := make([]object.Object, value.Len())
+		for i := range elements {
+			elements[i] = FromGo(value.Index(i).Interface())
+		}
+		return &object.Array{Elements: elements}
+	case reflect.Map:
+		keys := value.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		hash := object.NewHash()
+		for _, k := range keys {
+			key := FromGo(k.Interface())
+			hashable, ok := key.(object.Hashable)
+			if !ok {
+				return newError("unusable as hash key: %s", key.Type())
+			}
+			hash.Set(hashable.HashKey(), object.HashPair{Key: key, Value: FromGo(value.MapIndex(k).Interface())})
+		}
+		return hash
+	case reflect.Ptr:
+		if value.IsNil() {
+			return NULL
+		}
+		return FromGo(value.Elem().Interface())
+	}
+
+	return newError("cannot convert Go value of type %T", v)
+}
+
+// nativeName retorna o nome da função nativa se ident usar o prefixo __native_
+func nativeName(ident string) (string, bool) {
+	if !strings.HasPrefix(ident, nativePrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(ident, nativePrefix), true
+}
@@ -0,0 +1,60 @@
+package eval
+
+import (
+	"reflect"
+	"testing"
+
+	"jotlango/internal/object"
+)
+
+func TestConvertArgIntegers(t *testing.T) {
+	tests := []struct {
+		obj  object.Object
+		t    reflect.Type
+		want interface{}
+	}{
+		{&object.Integer{Value: 255}, reflect.TypeOf(uint8(0)), uint8(255)},
+		{&object.Integer{Value: -128}, reflect.TypeOf(int8(0)), int8(-128)},
+		{&object.Integer{Value: 1 << 40}, reflect.TypeOf(int64(0)), int64(1 << 40)},
+		{&object.Integer{Value: 1 << 40}, reflect.TypeOf(uint64(0)), uint64(1 << 40)},
+		{&object.Float{Value: 3.0}, reflect.TypeOf(int32(0)), int32(3)},
+	}
+
+	for _, tt := range tests {
+		got, err := ConvertArg(tt.obj, tt.t)
+		if err != nil {
+			t.Errorf("%s to %s: %v", tt.obj.Inspect(), tt.t, err)
+			continue
+		}
+		if got.Interface() != tt.want {
+			t.Errorf("%s to %s: got %v, want %v", tt.obj.Inspect(), tt.t, got.Interface(), tt.want)
+		}
+	}
+}
+
+func TestConvertArgIntegerOverflow(t *testing.T) {
+	tests := []struct {
+		obj  object.Object
+		t    reflect.Type
+		want string
+	}{
+		{&object.Integer{Value: 256}, reflect.TypeOf(uint8(0)), "integer 256 out of range for uint8"},
+		{&object.Integer{Value: 300}, reflect.TypeOf(int8(0)), "integer 300 out of range for int8"},
+		{&object.Integer{Value: -1}, reflect.TypeOf(uint(0)), "integer -1 out of range for uint"},
+		{&object.Integer{Value: 1 << 31}, reflect.TypeOf(int32(0)), "integer 2147483648 out of range for int32"},
+		{&object.Float{Value: 70000.0}, reflect.TypeOf(uint16(0)), "integer 70000 out of range for uint16"},
+	}
+
+	for _, tt := range tests {
+		_, err := ConvertArg(tt.obj, tt.t)
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("%s to %s: err = %v, want %q", tt.obj.Inspect(), tt.t, err, tt.want)
+		}
+	}
+
+	fn := WrapNative("port", func(port uint16) uint16 { return port })
+	result := fn(&object.Integer{Value: 70000})
+	if errObj, ok := result.(*object.Error); !ok || errObj.Message != "argument 1 to `port`: integer 70000 out of range for uint16" {
+		t.Errorf("got %s, want an out of range error", result.Inspect())
+	}
+}
@@ -0,0 +1,242 @@
+package eval
+
+import (
+	"math"
+
+	"jotlango/internal/object"
+)
+
+// isNumber indica se o objeto é um Integer ou um Float
+func isNumber(obj object.Object) bool {
+	switch obj.(type) {
+	case *object.Integer, *object.Float:
+		return true
+	}
+	return false
+}
+
+// toFloat converte um Integer ou Float para float64
+func toFloat(obj object.Object) float64 {
+	switch obj := obj.(type) {
+	case *object.Integer:
+		return float64(obj.Value)
+	case *object.Float:
+		return obj.Value
+	}
+	return 0
+}
+
+// evalNumberInfixExpression opera sobre dois inteiros com aritmética
+// inteira; se um dos lados for Float, os dois são convertidos para float
+func evalNumberInfixExpression(operator string, left, right object.Object) object.Object {
+	leftInt, leftOk := left.(*object.Integer)
+	rightInt, rightOk := right.(*object.Integer)
+	if leftOk && rightOk {
+		return evalIntegerInfixExpression(operator, leftInt.Value, rightInt.Value)
+	}
+	return evalFloatInfixExpression(operator, left, right)
+}
+
+// evalIntegerInfixExpression trata overflow como erro em vez de deixar o
+// valor dar a volta. A divisão é truncada em direção a zero e o resto tem o
+// sinal do dividendo; math.div e math.mod arredondam para baixo
+func evalIntegerInfixExpression(operator string, a, b int64) object.Object {
+	overflow := func() object.Object {
+		return newError("integer overflow: %d %s %d", a, operator, b)
+	}
+
+	switch operator {
+	case "+":
+		c := a + b
+		if (a^c)&(b^c) < 0 {
+			return overflow()
+		}
+		return &object.Integer{Value: c}
+	case "-":
+		c := a - b
+		if (a^b)&(a^c) < 0 {
+			return overflow()
+		}
+		return &object.Integer{Value: c}
+	case "*":
+		c, ok := multiply(a, b)
+		if !ok {
+			return overflow()
+		}
+		return &object.Integer{Value: c}
+	case "/":
+		if b == 0 {
+			return newError("division by zero")
+		}
+		if a == math.MinInt64 && b == -1 {
+			return overflow()
+		}
+		return &object.Integer{Value: a / b}
+	case "%":
+		if b == 0 {
+			return newError("modulo by zero")
+		}
+		if b == -1 {
+			return &object.Integer{Value: 0}
+		}
+		return &object.Integer{Value: a % b}
+	case "**":
+		return evalIntegerPower(a, b)
+	case "&":
+		return &object.Integer{Value: a & b}
+	case "|":
+		return &object.Integer{Value: a | b}
+	case "^":
+		return &object.Integer{Value: a ^ b}
+	case "<<", ">>":
+		if b < 0 {
+			return newError("negative shift count: %d %s %d", a, operator, b)
+		}
+		if b > 63 {
+			b = 63
+		}
+		if operator == ">>" {
+			return &object.Integer{Value: a >> b}
+		}
+		c := a << b
+		if c>>b != a {
+			return overflow()
+		}
+		return &object.Integer{Value: c}
+	case "..":
+		return &object.Range{Start: a, End: b}
+	case "<":
+		return nativeBoolToBooleanObject(a < b)
+	case ">":
+		return nativeBoolToBooleanObject(a > b)
+	case "<=":
+		return nativeBoolToBooleanObject(a <= b)
+	case ">=":
+		return nativeBoolToBooleanObject(a >= b)
+	case "==":
+		return nativeBoolToBooleanObject(a == b)
+	case "!=":
+		return nativeBoolToBooleanObject(a != b)
+	default:
+		return newError("unknown operator: %s %s %s", object.INTEGER_OBJ, operator, object.INTEGER_OBJ)
+	}
+}
+
+// evalIntegerPower calcula a ** b por exponenciação binária, verificando
+// overflow a cada multiplicação. Com expoente negativo o resultado é Float
+func evalIntegerPower(a, b int64) object.Object {
+	if b < 0 {
+		return evalFloatInfixExpression("**", &object.Integer{Value: a}, &object.Integer{Value: b})
+	}
+
+	result, base := int64(1), a
+	for exp := b; exp > 0; exp >>= 1 {
+		if exp&1 == 1 {
+			product, ok := multiply(result, base)
+			if !ok {
+				return newError("integer overflow: %d ** %d", a, b)
+			}
+			result = product
+		}
+		if exp > 1 {
+			square, ok := multiply(base, base)
+			if !ok {
+				return newError("integer overflow: %d ** %d", a, b)
+			}
+			base = square
+		}
+	}
+	return &object.Integer{Value: result}
+}
+
+// multiply retorna a * b e false se o resultado não couber em um int64
+func multiply(a, b int64) (int64, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+	c := a * b
+	if c/b != a || (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+		return 0, false
+	}
+	return c, true
+}
+
+// evalFloatInfixExpression nunca produz NaN ou infinito: divisão por zero
+// e resultados fora do alcance de float64 são erros
+func evalFloatInfixExpression(operator string, left, right object.Object) object.Object {
+	leftVal := toFloat(left)
+	rightVal := toFloat(right)
+
+	var result float64
+	switch operator {
+	case "+":
+		result = leftVal + rightVal
+	case "-":
+		result = leftVal - rightVal
+	case "*":
+		result = leftVal * rightVal
+	case "/":
+		if rightVal == 0 {
+			return newError("division by zero")
+		}
+		result = leftVal / rightVal
+	case "%":
+		if rightVal == 0 {
+			return newError("modulo by zero")
+		}
+		result = math.Mod(leftVal, rightVal)
+	case "**":
+		result = math.Pow(leftVal, rightVal)
+		if math.IsNaN(result) {
+			return newError("%s ** %s is not a real number", left.Inspect(), right.Inspect())
+		}
+	case "&", "|", "^", "<<", ">>":
+		return newError("bitwise operator %s requires INTEGER operands, got %s and %s", operator, left.Type(), right.Type())
+	case "..":
+		return newError("range bounds must be integers, got %s..%s", left.Inspect(), right.Inspect())
+	case "<":
+		return nativeBoolToBooleanObject(leftVal < rightVal)
+	case ">":
+		return nativeBoolToBooleanObject(leftVal > rightVal)
+	case "<=":
+		return nativeBoolToBooleanObject(leftVal <= rightVal)
+	case ">=":
+		return nativeBoolToBooleanObject(leftVal >= rightVal)
+	case "==":
+		return nativeBoolToBooleanObject(leftVal == rightVal)
+	case "!=":
+		return nativeBoolToBooleanObject(leftVal != rightVal)
+	default:
+		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+
+	if math.IsInf(result, 0) || math.IsNaN(result) {
+		return newError("float overflow: %s %s %s", left.Inspect(), operator, right.Inspect())
+	}
+	return &object.Float{Value: result}
+}
+
+// evalBitNotOperatorExpression inverte os bits de um inteiro
+func evalBitNotOperatorExpression(right object.Object) object.Object {
+	n, ok := right.(*object.Integer)
+	if !ok {
+		return newError("unknown operator: ~%s", right.Type())
+	}
+	return &object.Integer{Value: ^n.Value}
+}
+
+// evalMinusPrefixOperatorExpression nega um número; -(-9223372036854775808)
+// não cabe em um Integer
+func evalMinusPrefixOperatorExpression(right object.Object) object.Object {
+	switch right := right.(type) {
+	case *object.Integer:
+		if right.Value == math.MinInt64 {
+			return newError("integer overflow: -(%d)", right.Value)
+		}
+		return &object.Integer{Value: -right.Value}
+	case *object.Float:
+		return &object.Float{Value: -right.Value}
+	default:
+		return newError("unknown operator: -%s", right.Type())
+	}
+}
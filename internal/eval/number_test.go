@@ -0,0 +1,63 @@
+package eval
+
+import (
+	"testing"
+
+	"jotlango/internal/object"
+)
+
+func TestNumberOperators(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"1 + 2 * 3 ** 2", "19"},
+		{"2 ** 3 ** 2", "512"},
+		{"-2 ** 2", "-4"},
+		{"2 ** -1", "0.5"},
+		{"2.0 ** 2", "4.0"},
+		{"(0 - 2) ** 63", "-9223372036854775808"},
+		{"7 % 3", "1"},
+		{"-7 % 3", "-1"},
+		{"5 <= 5", "true"},
+		{"5 >= 6", "false"},
+		{"1.5 <= 2", "true"},
+		{"6 & 3", "2"},
+		{"6 | 3", "7"},
+		{"6 ^ 3", "5"},
+		{"~5", "-6"},
+		{"1 << 10", "1024"},
+		{"-16 >> 2", "-4"},
+		{"5 & 1 == 1", "true"},
+		{"1 | 2 ^ 3 & 4", "3"},
+	}
+
+	for _, tt := range tests {
+		expectValue(t, tt.input, tt.want)
+	}
+}
+
+func TestNumberOperatorErrors(t *testing.T) {
+	tests := []struct {
+		input   string
+		message string
+	}{
+		{"2 ** 64", "integer overflow: 2 ** 64"},
+		{"1 << 63", "integer overflow: 1 << 63"},
+		{"1 << -1", "negative shift count: 1 << -1"},
+		{"1.5 & 1", "bitwise operator & requires INTEGER operands, got FLOAT and INTEGER"},
+		{"~1.5", "unknown operator: ~FLOAT"},
+		{"(0 - 8) ** 0.5", "-8 ** 0.5 is not a real number"},
+	}
+
+	for _, tt := range tests {
+		errObj, ok := testEval(t, tt.input).(*object.Error)
+		if !ok {
+			t.Errorf("%q: expected an error", tt.input)
+			continue
+		}
+		if errObj.Message != tt.message {
+			t.Errorf("%q: message = %q, want %q", tt.input, errObj.Message, tt.message)
+		}
+	}
+}
@@ -0,0 +1,308 @@
+package eval
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"jotlango/internal/ast"
+	"jotlango/internal/object"
+)
+
+// Strings são indexadas por caractere (rune), não por byte: len("ação") é
+// 4 e "ação"[2] é "ç". Todas as posições aceitas e retornadas pelos
+// métodos abaixo seguem a mesma regra
+
+// maxStringLength limita o tamanho, em bytes, das strings criadas por
+// repeat, padStart e padEnd, para que um argumento grande demais seja um
+// erro em vez de esgotar a memória
+const maxStringLength = 1 << 30
+
+// stringMethods são os métodos chamados com s.nome(args). Strings são
+// imutáveis: os métodos sempre retornam um novo valor
+var stringMethods = map[string]func(s *object.String, args ...object.Object) object.Object{
+	// split divide s em volta de sep. Sem argumentos, divide nos espaços em
+	// branco e descarta partes vazias; com sep vazio, separa os caracteres
+	"split": func(s *object.String, args ...object.Object) object.Object {
+		if len(args) > 1 {
+			return newError("wrong number of arguments to split. got=%d, want=0 or 1", len(args))
+		}
+		var parts []string
+		if len(args) == 0 {
+			parts = strings.Fields(s.Value)
+		} else {
+			sep, err := stringArg("split", args, 0)
+			if err != nil {
+				return err
+			}
+			parts = strings.Split(s.Value, sep)
+		}
+		return stringArray(parts)
+	},
+	// chars retorna os caracteres de s
+	"chars": func(s *object.String, args ...object.Object) object.Object {
+		if len(args) != 0 {
+			return newError("wrong number of arguments to chars. got=%d, want=0", len(args))
+		}
+		return stringArray(strings.Split(s.Value, ""))
+	},
+	"trim": func(s *object.String, args ...object.Object) object.Object {
+		if len(args) != 0 {
+			return newError("wrong number of arguments to trim. got=%d, want=0", len(args))
+		}
+		return &object.String{Value: strings.TrimSpace(s.Value)}
+	},
+	"upper": func(s *object.String, args ...object.Object) object.Object {
+		if len(args) != 0 {
+			return newError("wrong number of arguments to upper. got=%d, want=0", len(args))
+		}
+		return &object.String{Value: strings.ToUpper(s.Value)}
+	},
+	"lower": func(s *object.String, args ...object.Object) object.Object {
+		if len(args) != 0 {
+			return newError("wrong number of arguments to lower. got=%d, want=0", len(args))
+		}
+		return &object.String{Value: strings.ToLower(s.Value)}
+	},
+	"startsWith": func(s *object.String, args ...object.Object) object.Object {
+		prefix, err := singleStringArg("startsWith", args)
+		if err != nil {
+			return err
+		}
+		return nativeBoolToBooleanObject(strings.HasPrefix(s.Value, prefix))
+	},
+	"endsWith": func(s *object.String, args ...object.Object) object.Object {
+		suffix, err := singleStringArg("endsWith", args)
+		if err != nil {
+			return err
+		}
+		return nativeBoolToBooleanObject(strings.HasSuffix(s.Value, suffix))
+	},
+	"contains": func(s *object.String, args ...object.Object) object.Object {
+		sub, err := singleStringArg("contains", args)
+		if err != nil {
+			return err
+		}
+		return nativeBoolToBooleanObject(strings.Contains(s.Value, sub))
+	},
+	// replace troca todas as ocorrências de old por new
+	"replace": func(s *object.String, args ...object.Object) object.Object {
+		if len(args) != 2 {
+			return newError("wrong number of arguments to replace. got=%d, want=2", len(args))
+		}
+		old, err := stringArg("replace", args, 0)
+		if err != nil {
+			return err
+		}
+		replacement, err := stringArg("replace", args, 1)
+		if err != nil {
+			return err
+		}
+		return &object.String{Value: strings.ReplaceAll(s.Value, old, replacement)}
+	},
+	// indexOf retorna a posição da primeira ocorrência de sub, ou -1
+	"indexOf": func(s *object.String, args ...object.Object) object.Object {
+		sub, err := singleStringArg("indexOf", args)
+		if err != nil {
+			return err
+		}
+		i := strings.Index(s.Value, sub)
+		if i >= 0 {
+			i = utf8.RuneCountInString(s.Value[:i])
+		}
+		return &object.Integer{Value: int64(i)}
+	},
+	// substring(inicio, fim) é o mesmo que s[inicio:fim]; sem fim, vai até
+	// o final da string
+	"substring": func(s *object.String, args ...object.Object) object.Object {
+		if len(args) != 1 && len(args) != 2 {
+			return newError("wrong number of arguments to substring. got=%d, want=1 or 2", len(args))
+		}
+		var end object.Object
+		if len(args) == 2 {
+			end = args[1]
+		}
+		return evalSliceExpression(s, args[0], end)
+	},
+	"repeat": func(s *object.String, args ...object.Object) object.Object {
+		if len(args) != 1 {
+			return newError("wrong number of arguments to repeat. got=%d, want=1", len(args))
+		}
+		n, ok := args[0].(*object.Integer)
+		if !ok || n.Value < 0 {
+			return newError("argument to repeat must be a non-negative INTEGER, got %s", args[0].Inspect())
+		}
+		if len(s.Value) > 0 && n.Value > maxStringLength/int64(len(s.Value)) {
+			return newError("repeat: result would exceed %d bytes", maxStringLength)
+		}
+		return &object.String{Value: strings.Repeat(s.Value, int(n.Value))}
+	},
+	// padStart e padEnd completam s até width caracteres repetindo pad,
+	// que por padrão é um espaço
+	"padStart": func(s *object.String, args ...object.Object) object.Object {
+		return pad("padStart", s, args, true)
+	},
+	"padEnd": func(s *object.String, args ...object.Object) object.Object {
+		return pad("padEnd", s, args, false)
+	},
+	// format substitui {} pelos argumentos em ordem e {0}, {1}... pelo
+	// argumento daquela posição. {{ e }} produzem chaves literais
+	"format": func(s *object.String, args ...object.Object) object.Object {
+		return formatString(s.Value, args)
+	},
+}
+
+// stringMethod retorna o método name associado a s
+func stringMethod(s *object.String, name string) (*object.Builtin, bool) {
+	method, ok := stringMethods[name]
+	if !ok {
+		return nil, false
+	}
+	return &object.Builtin{Fn: func(args ...object.Object) object.Object {
+		return method(s, args...)
+	}}, true
+}
+
+func stringArg(method string, args []object.Object, i int) (string, *object.Error) {
+	s, ok := args[i].(*object.String)
+	if !ok {
+		return "", newError("argument %d to %s must be STRING, got %s", i+1, method, args[i].Type())
+	}
+	return s.Value, nil
+}
+
+func singleStringArg(method string, args []object.Object) (string, *object.Error) {
+	if len(args) != 1 {
+		return "", newError("wrong number of arguments to %s. got=%d, want=1", method, len(args))
+	}
+	return stringArg(method, args, 0)
+}
+
+func stringArray(parts []string) *object.Array {
+	elements := make([]object.Object, len(parts))
+	for i, part := range parts {
+		elements[i] = &object.String{Value: part}
+	}
+	return &object.Array{Elements: elements}
+}
+
+func pad(method string, s *object.String, args []object.Object, start bool) object.Object {
+	if len(args) != 1 && len(args) != 2 {
+		return newError("wrong number of arguments to %s. got=%d, want=1 or 2", method, len(args))
+	}
+	width, ok := args[0].(*object.Integer)
+	if !ok {
+		return newError("argument 1 to %s must be INTEGER, got %s", method, args[0].Type())
+	}
+	fill := " "
+	if len(args) == 2 {
+		value, err := stringArg(method, args, 1)
+		if err != nil {
+			return err
+		}
+		if value == "" {
+			return newError("argument 2 to %s must not be empty", method)
+		}
+		fill = value
+	}
+
+	if width.Value > maxStringLength/utf8.UTFMax {
+		return newError("%s: width %d is too large", method, width.Value)
+	}
+
+	missing := int(width.Value) - utf8.RuneCountInString(s.Value)
+	if missing <= 0 {
+		return s
+	}
+
+	fillRunes := []rune(fill)
+	padding := make([]rune, missing)
+	for i := range padding {
+		padding[i] = fillRunes[i%len(fillRunes)]
+	}
+
+	if start {
+		return &object.String{Value: string(padding) + s.Value}
+	}
+	return &object.String{Value: s.Value + string(padding)}
+}
+
+func formatString(format string, args []object.Object) object.Object {
+	var out strings.Builder
+	next := 0
+
+	for i := 0; i < len(format); i++ {
+		c := format[i]
+		switch {
+		case c == '{' && i+1 < len(format) && format[i+1] == '{':
+			out.WriteByte('{')
+			i++
+		case c == '}' && i+1 < len(format) && format[i+1] == '}':
+			out.WriteByte('}')
+			i++
+		case c == '{':
+			end := strings.IndexByte(format[i:], '}')
+			if end < 0 {
+				return newError("format: unclosed { at position %d", utf8.RuneCountInString(format[:i]))
+			}
+			field := format[i+1 : i+end]
+
+			index := next
+			if field == "" {
+				next++
+			} else {
+				n, err := strconv.Atoi(field)
+				if err != nil || n < 0 {
+					return newError("format: invalid placeholder {%s}", field)
+				}
+				index = n
+			}
+			if index >= len(args) {
+				return newError("format: placeholder {%s} needs argument %d, got %d arguments", field, index, len(args))
+			}
+
+			out.WriteString(args[index].Inspect())
+			i += end
+		case c == '}':
+			return newError("format: unmatched } at position %d", utf8.RuneCountInString(format[:i]))
+		default:
+			out.WriteByte(c)
+		}
+	}
+
+	return &object.String{Value: out.String()}
+}
+
+// evalStringIndexExpression retorna o caractere na posição index como uma
+// string de tamanho 1, com as mesmas regras de limites dos arrays
+func evalStringIndexExpression(s *object.String, index object.Object) object.Object {
+	n, ok := index.(*object.Integer)
+	if !ok {
+		return newError("string index must be INTEGER, got %s", index.Type())
+	}
+
+	runes := []rune(s.Value)
+	i, ok := resolveIndex(n.Value, len(runes))
+	if !ok {
+		if strictIndex {
+			return indexOutOfRange(n.Value, len(runes))
+		}
+		return NULL
+	}
+
+	return &object.String{Value: string(runes[i])}
+}
+
+// evalTemplateLiteral concatena os trechos de uma string com interpolação.
+// Valores que não são strings são formatados como em print
+func evalTemplateLiteral(node *ast.TemplateLiteral, env *object.Environment) object.Object {
+	var out strings.Builder
+	for _, part := range node.Parts {
+		value := Eval(part, env)
+		if isError(value) {
+			return value
+		}
+		out.WriteString(value.Inspect())
+	}
+	return &object.String{Value: out.String()}
+}
@@ -0,0 +1,72 @@
+package eval
+
+import "testing"
+
+func TestStringMethods(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`"Olá, Mundo".split(", ")`, "[Olá, Mundo]"},
+		{`"a,b,,c".split(",")`, "[a, b, , c]"},
+		{`"abc".split("")`, "[a, b, c]"},
+		{`"héllo".chars()`, "[h, é, l, l, o]"},
+		{"\"[\" + \"  x y \\n\".trim() + \"]\"", "[x y]"},
+		{`"Olá".upper()`, "OLÁ"},
+		{`"ÁRVORE".lower()`, "árvore"},
+		{`"Olá, Mundo".startsWith("Olá")`, "true"},
+		{`"Olá, Mundo".endsWith("Olá")`, "false"},
+		{`"Olá, Mundo".contains("á, M")`, "true"},
+		{`"aaa".replace("a", "b")`, "bbb"},
+		// indexOf e substring contam caracteres, não bytes
+		{`"Olá, Mundo".indexOf("Mundo")`, "5"},
+		{`"Olá".indexOf("z")`, "-1"},
+		{`"éa".indexOf("a")`, "1"},
+		{`"Olá, Mundo".substring(5)`, "Mundo"},
+		{`"Olá, Mundo".substring(0, 3)`, "Olá"},
+		{`"Olá, Mundo".substring(-5)`, "Mundo"},
+		{`"ab".repeat(3)`, "ababab"},
+		{`"ab".repeat(0)`, ""},
+		{`"7".padStart(3, "0")`, "007"},
+		{`"7".padEnd(3) + "|"`, "7  |"},
+		{`"é".padStart(3, "*")`, "**é"},
+		{`"abcd".padStart(2)`, "abcd"},
+		{`"{} + {} = {}".format(1, 2, 3)`, "1 + 2 = 3"},
+		{`"{1}{0}{1}".format("a", "b")`, "bab"},
+		{`"{{}} {}".format([1])`, "{} [1]"},
+		// Métodos também funcionam em variáveis e no resultado de outros
+		{"var s = \"  Ana \"\ns.trim().upper().padEnd(5, \".\")", "ANA.."},
+	}
+
+	for _, tt := range tests {
+		expectValue(t, tt.input, tt.want)
+	}
+
+	expectError(t, `"a".repeat(-1)`, "argument to repeat must be a non-negative INTEGER, got -1", 1, 11)
+	expectError(t, `"a".nope()`, "undefined method nope on STRING", 1, 4)
+	expectError(t, `"a".split(1)`, "argument 1 to split must be STRING, got INTEGER", 1, 10)
+	expectError(t, `"a".padStart(3, "")`, "argument 2 to padStart must not be empty", 1, 13)
+	expectError(t, `"{} {}".format(1)`, "format: placeholder {} needs argument 1, got 1 arguments", 1, 15)
+	expectError(t, `"{x}".format(1)`, "format: invalid placeholder {x}", 1, 13)
+	expectError(t, `"a}".format()`, "format: unmatched } at position 1", 1, 12)
+}
+
+// len, índices, fatias e for contam caracteres, não bytes
+func TestStringRunes(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`len("héllo😀")`, "6"},
+		{`"héllo"[1]`, "é"},
+		{`"héllo"[-1]`, "o"},
+		{`"abc"[10]`, "null"},
+		{`"héllo"[1:3]`, "él"},
+		{`"😀ab"[:1]`, "😀"},
+		{"var out = []\nfor i, c in \"aé\" { out = push(out, \"${i}${c}\") }\nout", "[0a, 1é]"},
+	}
+
+	for _, tt := range tests {
+		expectValue(t, tt.input, tt.want)
+	}
+}
@@ -0,0 +1,40 @@
+package eval
+
+import "jotlango/internal/object"
+
+func init() {
+	builtins["try"] = &object.Builtin{Fn: builtinTry}
+}
+
+// try chama fn sem argumentos e transforma um erro em valor, em vez de
+// interromper o programa. Retorna {"ok": true, "value": resultado,
+// "error": null} ou {"ok": false, "value": null, "error": mensagem}
+func builtinTry(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("wrong number of arguments to try. got=%d, want=1", len(args))
+	}
+	if err := functionArg("try", args, 0); err != nil {
+		return err
+	}
+
+	value := applyFunction(args[0], nil)
+	if errObj, ok := value.(*object.Error); ok {
+		return tryResult(FALSE, NULL, &object.String{Value: errObj.Message})
+	}
+	if value == nil {
+		value = NULL
+	}
+	return tryResult(TRUE, value, NULL)
+}
+
+func tryResult(ok, value, err object.Object) *object.Hash {
+	hash := object.NewHash()
+	for _, field := range []struct {
+		name  string
+		value object.Object
+	}{{"ok", ok}, {"value", value}, {"error", err}} {
+		key := &object.String{Value: field.name}
+		hash.Set(key.HashKey(), object.HashPair{Key: key, Value: field.value})
+	}
+	return hash
+}
@@ -0,0 +1,26 @@
+package eval
+
+import "testing"
+
+func TestTry(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"try(fn() { 1 + 2 })", "{ok: true, value: 3, error: null}"},
+		{"try(fn() { 1 / 0 })", "{ok: false, value: null, error: division by zero}"},
+		{"try(fn() { null })", "{ok: true, value: null, error: null}"},
+		{"try(fn() { return 4 })[\"value\"]", "4"},
+		{"try(fn() { map([1, 0], fn(x) { 1 / x }) })[\"error\"]", "division by zero"},
+		// O programa continua depois do erro
+		{"var r = try(fn() { undefinedName })\nif r[\"ok\"] { 1 } else { 2 }", "2"},
+		{"try(fn() { try(fn() { 1 / 0 }) })[\"value\"][\"ok\"]", "false"},
+		{"try(fn(x) { x })[\"error\"]", "wrong number of arguments. got=0, want=1"},
+	}
+
+	for _, tt := range tests {
+		expectValue(t, tt.input, tt.want)
+	}
+
+	expectError(t, "try(1)", "argument 1 to `try` must be a function, got INTEGER", 1, 4)
+}
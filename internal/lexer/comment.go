@@ -0,0 +1,55 @@
+package lexer
+
+import "strings"
+
+// skipComment pula um comentário de linha. Comentários que começam com
+// exatamente três barras são de documentação: o texto é guardado e
+// associado ao próximo token, para que o parser o ligue à declaração
+// seguinte
+func (l *Lexer) skipComment() {
+	l.readChar() // primeira /
+	l.readChar() // segunda /
+	isDoc := l.ch == '/' && l.peekChar() != '/'
+	if isDoc {
+		l.readChar()
+	}
+
+	start := l.position
+	for l.ch != '\n' && l.ch != 0 {
+		l.readChar()
+	}
+
+	if isDoc {
+		text := strings.TrimSuffix(l.input[start:l.position], "\r")
+		l.doc = append(l.doc, strings.TrimPrefix(text, " "))
+	}
+
+	l.skipWhitespace()
+}
+
+// skipBlockComment pula um comentário /* ... */. Comentários de bloco
+// podem ser aninhados, o que permite comentar um trecho que já contém
+// outro comentário. Se o comentário não for fechado, retorna um TokenError
+func (l *Lexer) skipBlockComment() (Token, bool) {
+	start := l.pos()
+	depth := 0
+
+	for {
+		switch {
+		case l.ch == 0:
+			return Token{Type: TokenError, Literal: "unterminated block comment", Pos: start}, false
+		case l.ch == '/' && l.peekChar() == '*':
+			depth++
+			l.readChar()
+		case l.ch == '*' && l.peekChar() == '/':
+			depth--
+			l.readChar()
+			if depth == 0 {
+				l.readChar()
+				l.skipWhitespace()
+				return Token{}, true
+			}
+		}
+		l.readChar()
+	}
+}
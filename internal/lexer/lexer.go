@@ -1,17 +1,59 @@
 package lexer
 
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
 // TokenType representa o tipo de um token
 type TokenType string
 
+// Position representa a localização de um token no código-fonte
+type Position struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+}
+
+// String formata a posição como file:line:col
+func (p Position) String() string {
+	file := p.File
+	if file == "" {
+		file = "<input>"
+	}
+	return fmt.Sprintf("%s:%d:%d", file, p.Line, p.Column)
+}
+
+// IsValid indica se a posição foi preenchida pelo lexer
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
 // Token representa um token do lexer
 type Token struct {
 	Type    TokenType
 	Literal string
+	Pos     Position
+
+	// End é a posição logo após o último caractere do token no código-fonte.
+	// Difere de Pos mais o tamanho de Literal em strings com escapes, cujo
+	// Literal é o valor já decodificado
+	End Position
+
+	// Parts tem os trechos de um TokenTemplate
+	Parts []TemplatePart
+
+	// Doc tem o texto dos comentários /// logo antes do token, uma linha
+	// por comentário
+	Doc string
 }
 
 // Constantes para os tipos de tokens
 const (
 	TokenIllegal = "ILLEGAL"
+	TokenError   = "ERROR" // literal ou comentário malformado; Literal tem a mensagem
 	TokenEOF     = "EOF"
 
 	// Identificadores + literais
@@ -19,6 +61,8 @@ const (
 	TokenInt    = "INT"
 	TokenFloat  = "FLOAT"
 	TokenString = "STRING"
+	// TokenTemplate é uma string com ${...}; Literal tem o conteúdo original
+	TokenTemplate = "TEMPLATE"
 
 	// Operadores
 	TokenAssign   = "="
@@ -27,13 +71,34 @@ const (
 	TokenBang     = "!"
 	TokenAsterisk = "*"
 	TokenSlash    = "/"
+	TokenPercent  = "%"
+	TokenPower    = "**"
 	TokenLT       = "<"
 	TokenGT       = ">"
+	TokenLTE      = "<="
+	TokenGTE      = ">="
 	TokenEQ       = "=="
 	TokenNotEQ    = "!="
+	TokenAnd      = "&&"
+	TokenOr       = "||"
+	TokenBitAnd   = "&"
+	TokenBitOr    = "|"
+	TokenBitXor   = "^"
+	TokenBitNot   = "~"
+	TokenShl      = "<<"
+	TokenShr      = ">>"
 	TokenColon    = ":"
 	TokenNewLine  = "NEWLINE"
 
+	// Atribuição composta e incremento
+	TokenPlusAssign     = "+="
+	TokenMinusAssign    = "-="
+	TokenAsteriskAssign = "*="
+	TokenSlashAssign    = "/="
+	TokenPercentAssign  = "%="
+	TokenIncrement      = "++"
+	TokenDecrement      = "--"
+
 	// Delimitadores
 	TokenComma     = ","
 	TokenSemicolon = ";"
@@ -42,6 +107,7 @@ const (
 	TokenLBrace    = "{"
 	TokenRBrace    = "}"
 	TokenDot       = "."
+	TokenDotDot    = ".."
 	TokenLBracket  = "["
 	TokenRBracket  = "]"
 
@@ -50,6 +116,7 @@ const (
 	TokenLet        = "let"
 	TokenTrue       = "true"
 	TokenFalse      = "false"
+	TokenNull       = "null"
 	TokenIf         = "if"
 	TokenElse       = "else"
 	TokenReturn     = "return"
@@ -64,98 +131,171 @@ const (
 	TokenTypeBool   = "bool"
 	TokenCall       = "call"
 	TokenPrint      = "print"
+	TokenWhile      = "while"
+	TokenFor        = "for"
+	TokenIn         = "in"
+	TokenBreak      = "break"
+	TokenContinue   = "continue"
+	TokenInterface  = "interface"
+	TokenImport     = "import"
+	TokenAs         = "as"
 )
 
 var keywords = map[string]TokenType{
-	"fn":     TokenFunction,
-	"class":  TokenClass,
-	"prop":   TokenProp,
-	"true":   TokenTrue,
-	"false":  TokenFalse,
-	"if":     TokenIf,
-	"else":   TokenElse,
-	"return": TokenReturn,
-	"new":    TokenNew,
-	"var":    TokenVar,
-	"void":   TokenVoid,
-	"int":    TokenTypeInt,
-	"float":  TokenTypeFloat,
-	"string": TokenTypeString,
-	"bool":   TokenTypeBool,
-	"call":   TokenCall,
-	"print":  TokenPrint,
+	"fn":        TokenFunction,
+	"class":     TokenClass,
+	"prop":      TokenProp,
+	"true":      TokenTrue,
+	"false":     TokenFalse,
+	"null":      TokenNull,
+	"if":        TokenIf,
+	"else":      TokenElse,
+	"return":    TokenReturn,
+	"new":       TokenNew,
+	"var":       TokenVar,
+	"void":      TokenVoid,
+	"int":       TokenTypeInt,
+	"float":     TokenTypeFloat,
+	"string":    TokenTypeString,
+	"bool":      TokenTypeBool,
+	"call":      TokenCall,
+	"print":     TokenPrint,
+	"while":     TokenWhile,
+	"for":       TokenFor,
+	"in":        TokenIn,
+	"break":     TokenBreak,
+	"continue":  TokenContinue,
+	"interface": TokenInterface,
+	"import":    TokenImport,
+	"as":        TokenAs,
 }
 
-// Lexer representa o analisador léxico
+// Lexer representa o analisador léxico. O input é lido como UTF-8, um
+// caractere (rune) por vez; position e readPosition são offsets em bytes,
+// mas as colunas contam caracteres
 type Lexer struct {
 	input        string
 	position     int  // posição atual no input (aponta para o caractere atual)
 	readPosition int  // posição atual de leitura (após o caractere atual)
-	ch           byte // caractere atual sendo examinado
+	ch           rune // caractere atual sendo examinado
+	file         string
+	line         int      // linha do caractere atual (começa em 1)
+	column       int      // coluna do caractere atual (começa em 1)
+	doc          []string // comentários /// ainda não associados a um token
 }
 
+// bom é a marca de ordem de bytes que alguns editores gravam no início de
+// arquivos UTF-8
+const bom = "\uFEFF"
+
 // NewLexer cria um novo lexer
 func NewLexer(input string) *Lexer {
-	l := &Lexer{input: input}
+	return NewFileLexer("", input)
+}
+
+// NewFileLexer cria um novo lexer cujos tokens carregam o nome do arquivo.
+// Uma BOM no início do arquivo é ignorada
+func NewFileLexer(file string, input string) *Lexer {
+	return NewLexerAt(Position{File: file, Line: 1, Column: 1}, strings.TrimPrefix(input, bom))
+}
+
+// NewLexerAt cria um lexer para um trecho de código que começa em pos, como
+// a expressão de uma interpolação, para que os tokens tenham as posições do
+// arquivo original
+func NewLexerAt(pos Position, input string) *Lexer {
+	l := &Lexer{input: input, file: pos.File, line: pos.Line, column: pos.Column - 1}
 	l.readChar()
 	return l
 }
 
-// readChar lê o próximo caractere e avança a posição no input
+// readChar lê o próximo caractere e avança a posição no input. Bytes que
+// não formam UTF-8 válido são lidos como utf8.RuneError
 func (l *Lexer) readChar() {
+	if l.ch == '\n' {
+		l.line++
+		l.column = 0
+	}
+	if l.readPosition <= len(l.input) {
+		l.column++
+	}
+
+	size := 1
 	if l.readPosition >= len(l.input) {
 		l.ch = 0
 	} else {
-		l.ch = l.input[l.readPosition]
+		l.ch, size = utf8.DecodeRuneInString(l.input[l.readPosition:])
 	}
 	l.position = l.readPosition
-	l.readPosition++
+	l.readPosition += size
 }
 
 // NextToken retorna o próximo token do input
 func (l *Lexer) NextToken() Token {
-	var tok Token
-
 	l.skipWhitespace()
 
+	pos := l.pos()
+	tok := l.nextToken()
+	if !tok.Pos.IsValid() {
+		tok.Pos = pos
+	}
+	if !tok.End.IsValid() {
+		tok.End = l.pos()
+	}
+	if l.doc != nil {
+		tok.Doc = strings.Join(l.doc, "\n")
+		l.doc = nil
+	}
+	return tok
+}
+
+// pos retorna a posição do caractere atual
+func (l *Lexer) pos() Position {
+	return Position{File: l.file, Line: l.line, Column: l.column}
+}
+
+// nextToken reconhece o token que começa no caractere atual
+func (l *Lexer) nextToken() Token {
+	var tok Token
+
 	switch l.ch {
 	case '=':
-		if l.peekChar() == '=' {
-			ch := l.ch
-			l.readChar()
-			literal := string(ch) + string(l.ch)
-			tok = Token{Type: TokenEQ, Literal: literal}
-		} else {
-			tok = newToken(TokenAssign, l.ch)
-		}
+		tok = l.operatorToken(TokenAssign, map[rune]TokenType{'=': TokenEQ})
 	case '/':
 		if l.peekChar() == '/' {
 			l.skipComment()
 			return l.NextToken()
+		} else if l.peekChar() == '*' {
+			if errTok, ok := l.skipBlockComment(); !ok {
+				return errTok
+			}
+			return l.NextToken()
 		} else {
-			tok = newToken(TokenSlash, l.ch)
+			tok = l.operatorToken(TokenSlash, map[rune]TokenType{'=': TokenSlashAssign})
 		}
 	case ':':
 		tok = newToken(TokenColon, l.ch)
 	case '+':
-		tok = newToken(TokenPlus, l.ch)
+		tok = l.operatorToken(TokenPlus, map[rune]TokenType{'+': TokenIncrement, '=': TokenPlusAssign})
 	case '-':
-		tok = newToken(TokenMinus, l.ch)
+		tok = l.operatorToken(TokenMinus, map[rune]TokenType{'-': TokenDecrement, '=': TokenMinusAssign})
 	case '!':
-		if l.peekChar() == '=' {
-			ch := l.ch
-			l.readChar()
-			literal := string(ch) + string(l.ch)
-			tok = Token{Type: TokenNotEQ, Literal: literal}
-		} else {
-			tok = newToken(TokenBang, l.ch)
-		}
+		tok = l.operatorToken(TokenBang, map[rune]TokenType{'=': TokenNotEQ})
 	case '*':
-		tok = newToken(TokenAsterisk, l.ch)
+		tok = l.operatorToken(TokenAsterisk, map[rune]TokenType{'*': TokenPower, '=': TokenAsteriskAssign})
+	case '%':
+		tok = l.operatorToken(TokenPercent, map[rune]TokenType{'=': TokenPercentAssign})
 	case '<':
-		tok = newToken(TokenLT, l.ch)
+		tok = l.operatorToken(TokenLT, map[rune]TokenType{'=': TokenLTE, '<': TokenShl})
 	case '>':
-		tok = newToken(TokenGT, l.ch)
+		tok = l.operatorToken(TokenGT, map[rune]TokenType{'=': TokenGTE, '>': TokenShr})
+	case '&':
+		tok = l.operatorToken(TokenBitAnd, map[rune]TokenType{'&': TokenAnd})
+	case '|':
+		tok = l.operatorToken(TokenBitOr, map[rune]TokenType{'|': TokenOr})
+	case '^':
+		tok = newToken(TokenBitXor, l.ch)
+	case '~':
+		tok = newToken(TokenBitNot, l.ch)
 	case ',':
 		tok = newToken(TokenComma, l.ch)
 	case ';':
@@ -169,15 +309,20 @@ func (l *Lexer) NextToken() Token {
 	case '}':
 		tok = newToken(TokenRBrace, l.ch)
 	case '.':
-		tok = newToken(TokenDot, l.ch)
+		if l.peekChar() == '.' {
+			l.readChar()
+			tok = Token{Type: TokenDotDot, Literal: ".."}
+		} else {
+			tok = newToken(TokenDot, l.ch)
+		}
 	case '[':
 		tok = newToken(TokenLBracket, l.ch)
 	case ']':
 		tok = newToken(TokenRBracket, l.ch)
 	case '"':
-		tok.Type = TokenString
-		tok.Literal = l.readString()
-		return tok
+		return l.readString()
+	case '`':
+		return l.readRawString()
 	case '\n':
 		tok = newToken(TokenNewLine, l.ch)
 	case 0:
@@ -191,7 +336,9 @@ func (l *Lexer) NextToken() Token {
 		} else if isDigit(l.ch) {
 			return l.readNumber()
 		} else {
-			tok = newToken(TokenIllegal, l.ch)
+			// O literal guarda os bytes originais, para que UTF-8 inválido
+			// apareça como \xff na mensagem de erro
+			tok = Token{Type: TokenIllegal, Literal: l.input[l.position:l.readPosition]}
 		}
 	}
 
@@ -209,7 +356,7 @@ func (l *Lexer) skipWhitespace() {
 // readIdentifier lê um identificador e retorna seu valor
 func (l *Lexer) readIdentifier() string {
 	position := l.position
-	for isLetter(l.ch) {
+	for isLetter(l.ch) || isDigit(l.ch) || unicode.IsMark(l.ch) {
 		l.readChar()
 	}
 	return l.input[position:l.position]
@@ -222,7 +369,8 @@ func (l *Lexer) readNumber() Token {
 
 	for isDigit(l.ch) || l.ch == '.' {
 		if l.ch == '.' {
-			if isFloat {
+			// Só é ponto decimal se seguido de dígito; 0..10 é um intervalo
+			if isFloat || !isDigit(l.peekChar()) {
 				break
 			}
 			isFloat = true
@@ -237,39 +385,41 @@ func (l *Lexer) readNumber() Token {
 	return Token{Type: TokenInt, Literal: literal}
 }
 
-// readString lê uma string e retorna seu valor
-func (l *Lexer) readString() string {
-	position := l.position + 1
-	for {
-		l.readChar()
-		if l.ch == '"' || l.ch == 0 {
-			break
-		}
-	}
-	l.readChar() // avança o cursor após a aspas de fechamento
-	return l.input[position : l.position-1]
-}
-
 // peekChar retorna o próximo caractere sem avançar a posição
-func (l *Lexer) peekChar() byte {
+func (l *Lexer) peekChar() rune {
 	if l.readPosition >= len(l.input) {
 		return 0
 	}
-	return l.input[l.readPosition]
+	ch, _ := utf8.DecodeRuneInString(l.input[l.readPosition:])
+	return ch
+}
+
+// operatorToken lê um operador que começa no caractere atual. pairs diz o
+// tipo de cada operador de dois caracteres que começa com ele; se o próximo
+// caractere não formar um par, o token tem só o caractere atual
+func (l *Lexer) operatorToken(single TokenType, pairs map[rune]TokenType) Token {
+	if pair, ok := pairs[l.peekChar()]; ok {
+		ch := l.ch
+		l.readChar()
+		return Token{Type: pair, Literal: string(ch) + string(l.ch)}
+	}
+	return newToken(single, l.ch)
 }
 
 // newToken cria um novo token
-func newToken(tokenType TokenType, ch byte) Token {
+func newToken(tokenType TokenType, ch rune) Token {
 	return Token{Type: tokenType, Literal: string(ch)}
 }
 
-// isLetter verifica se um caractere é uma letra
-func isLetter(ch byte) bool {
-	return 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || ch == '_'
+// isLetter verifica se um caractere pode iniciar um identificador: letras
+// de qualquer alfabeto, como em função ou usuário, e _
+func isLetter(ch rune) bool {
+	return 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || ch == '_' ||
+		ch >= utf8.RuneSelf && unicode.IsLetter(ch)
 }
 
-// isDigit verifica se um caractere é um dígito
-func isDigit(ch byte) bool {
+// isDigit verifica se um caractere é um dígito ASCII
+func isDigit(ch rune) bool {
 	return '0' <= ch && ch <= '9'
 }
 
@@ -280,21 +430,3 @@ func lookupIdent(ident string) TokenType {
 	}
 	return TokenIdent
 }
-
-func (l *Lexer) skipComment() {
-	// Consumir o segundo '/'
-	l.readChar()
-
-	// Pular até encontrar uma nova linha ou EOF
-	for l.ch != '\n' && l.ch != 0 {
-		l.readChar()
-	}
-
-	// Pular a nova linha
-	if l.ch == '\n' {
-		l.readChar()
-	}
-
-	// Pular espaços em branco após o comentário
-	l.skipWhitespace()
-}
@@ -0,0 +1,375 @@
+package lexer
+
+import (
+	"testing"
+)
+
+// tokens lê input até EOF ou até o primeiro TokenError, que é incluído
+func tokens(input string) []Token {
+	l := NewLexer(input)
+	var result []Token
+	for {
+		tok := l.NextToken()
+		if tok.Type == TokenEOF {
+			return result
+		}
+		result = append(result, tok)
+		if tok.Type == TokenError {
+			return result
+		}
+	}
+}
+
+// single lê input, que deve ter exatamente um token
+func single(t *testing.T, input string) Token {
+	t.Helper()
+	toks := tokens(input)
+	if len(toks) != 1 {
+		t.Fatalf("%q: got %d tokens %v, want 1", input, len(toks), toks)
+	}
+	return toks[0]
+}
+
+func TestStringLiterals(t *testing.T) {
+	tests := []struct {
+		input    string
+		wantType TokenType
+		want     string
+	}{
+		{`"hello"`, TokenString, "hello"},
+		{`""`, TokenString, ""},
+		{`"a\nb"`, TokenString, "a\nb"},
+		{`"\t\r\0"`, TokenString, "\t\r\x00"},
+		{`"\"quoted\" \\ \$"`, TokenString, `"quoted" \ $`},
+		{`"\u{48}\u{e9}\u{1F600}"`, TokenString, "Hé😀"},
+		{`"$ and { alone"`, TokenString, "$ and { alone"},
+		{`"\${not}"`, TokenString, "${not}"},
+
+		// Aspas triplas: várias linhas, e a quebra após a abertura é ignorada
+		{"\"\"\"\nline 1\nline \"2\"\n\"\"\"", TokenString, "line 1\nline \"2\"\n"},
+		{`"""one line"""`, TokenString, "one line"},
+
+		// Crases: conteúdo como está, sem escapes nem interpolação
+		{"`C:\\dir\\n ${x}`", TokenString, `C:\dir\n ${x}`},
+		{"`a\nb`", TokenString, "a\nb"},
+
+		{`"abc`, TokenError, "unterminated string literal"},
+		{"\"abc\ndef\"", TokenError, "unterminated string literal"},
+		{`"""abc"`, TokenError, "unterminated string literal"},
+		{"`abc", TokenError, "unterminated raw string literal"},
+		{`"\q"`, TokenError, `invalid escape sequence \q`},
+		{`"\u0041"`, TokenError, `invalid unicode escape: expected \u{...}`},
+		{`"\u{41"`, TokenError, `invalid unicode escape: expected \u{...}`},
+		{`"\u{}"`, TokenError, `invalid unicode code point \u{}`},
+		{`"\u{110000}"`, TokenError, `invalid unicode code point \u{110000}`},
+		{`"\u{D800}"`, TokenError, `invalid unicode code point \u{D800}`},
+		{`"\u{zz}"`, TokenError, `invalid unicode escape: expected \u{...}`},
+	}
+
+	for _, tt := range tests {
+		tok := single(t, tt.input)
+		if tok.Type != tt.wantType || tok.Literal != tt.want {
+			t.Errorf("%q: got %s %q, want %s %q", tt.input, tok.Type, tok.Literal, tt.wantType, tt.want)
+		}
+	}
+}
+
+// O erro de um escape inválido aponta para a barra, não para o início da
+// string, e o resto da string é consumido
+func TestInvalidEscapePosition(t *testing.T) {
+	toks := tokens(`x = "ab\qc" + 1`)
+	if len(toks) != 3 {
+		t.Fatalf("got %v, want x, = and an error", toks)
+	}
+	tok := toks[2]
+	if tok.Pos.Column != 8 || tok.End.Column != 10 {
+		t.Errorf("error at %d-%d, want 8-10", tok.Pos.Column, tok.End.Column)
+	}
+
+	// Só o primeiro escape inválido é reportado
+	tok = single(t, `"\q \u{zz}"`)
+	if tok.Literal != `invalid escape sequence \q` {
+		t.Errorf("got %q, want the first invalid escape", tok.Literal)
+	}
+}
+
+func TestTemplateParts(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []TemplatePart
+	}{
+		{
+			`"Hello ${name}!"`,
+			[]TemplatePart{{Text: "Hello "}, {Expr: "name", IsExpr: true}, {Text: "!"}},
+		},
+		{
+			`"${a}${b}"`,
+			[]TemplatePart{{Text: ""}, {Expr: "a", IsExpr: true}, {Text: ""}, {Expr: "b", IsExpr: true}, {Text: ""}},
+		},
+		// Chaves e strings dentro da expressão não fecham a interpolação
+		{
+			`"total: ${ {"a": 1}["a"] }"`,
+			[]TemplatePart{{Text: "total: "}, {Expr: ` {"a": 1}["a"] `, IsExpr: true}, {Text: ""}},
+		},
+		{
+			`"${f("}")}"`,
+			[]TemplatePart{{Text: ""}, {Expr: `f("}")`, IsExpr: true}, {Text: ""}},
+		},
+		{
+			`"outer ${"inner ${x}"} end"`,
+			[]TemplatePart{{Text: "outer "}, {Expr: `"inner ${x}"`, IsExpr: true}, {Text: " end"}},
+		},
+		{
+			"\"${`}`}\"",
+			[]TemplatePart{{Text: ""}, {Expr: "`}`", IsExpr: true}, {Text: ""}},
+		},
+		// Escapes no texto são resolvidos; na expressão ficam como estão
+		{
+			`"a\n${x}\t"`,
+			[]TemplatePart{{Text: "a\n"}, {Expr: "x", IsExpr: true}, {Text: "\t"}},
+		},
+		{
+			"\"\"\"\n${a +\n b}\n\"\"\"",
+			[]TemplatePart{{Text: ""}, {Expr: "a +\n b", IsExpr: true}, {Text: "\n"}},
+		},
+	}
+
+	for _, tt := range tests {
+		tok := single(t, tt.input)
+		if tok.Type != TokenTemplate {
+			t.Errorf("%q: got %s %q, want a template", tt.input, tok.Type, tok.Literal)
+			continue
+		}
+		if len(tok.Parts) != len(tt.want) {
+			t.Errorf("%q: got %d parts %+v, want %d", tt.input, len(tok.Parts), tok.Parts, len(tt.want))
+			continue
+		}
+		for i, part := range tok.Parts {
+			want := tt.want[i]
+			if part.Text != want.Text || part.Expr != want.Expr || part.IsExpr != want.IsExpr {
+				t.Errorf("%q: part %d = %+v, want %+v", tt.input, i, part, want)
+			}
+		}
+	}
+}
+
+// A posição de uma expressão interpolada é a do seu primeiro caractere, para
+// que erros dentro dela apontem para o lugar certo do arquivo
+func TestTemplateExpressionPosition(t *testing.T) {
+	toks := tokens("x = 1\n  \"ab ${x + y} ${z}\"")
+	if len(toks) != 4 || toks[3].Type != TokenTemplate {
+		t.Fatalf("got %v, want a template as the fourth token", toks)
+	}
+	tok := toks[3]
+	for i, want := range []Position{{Line: 2, Column: 9}, {Line: 2, Column: 18}} {
+		if got := tok.Parts[2*i+1].Pos; got != want {
+			t.Errorf("expression %d at %s, want %s", i, got, want)
+		}
+	}
+}
+
+func TestUnterminatedInterpolation(t *testing.T) {
+	for _, input := range []string{
+		`"${x"`,
+		`"${x`,
+		"\"${\nx}\"",
+		`"${ "abc }"`,
+		"\"\"\"${ {x }\"\"\"",
+	} {
+		tok := single(t, input)
+		if tok.Type != TokenError {
+			t.Errorf("%q: got %s %q, want an error", input, tok.Type, tok.Literal)
+		}
+	}
+}
+
+// End aponta para depois das aspas de fechamento, mesmo quando Literal,
+// já decodificado, é mais curto que o código-fonte
+func TestStringEnd(t *testing.T) {
+	tests := []struct {
+		input   string
+		endLine int
+		endCol  int
+	}{
+		{`"abc"`, 1, 6},
+		{`"a\u{1F600}b"`, 1, 14},
+		{`"${x}"`, 1, 7},
+		{"\"\"\"\nab\n\"\"\"", 3, 4},
+		{"`a\nbc`", 2, 4},
+	}
+
+	for _, tt := range tests {
+		tok := single(t, tt.input)
+		if tok.Pos.Line != 1 || tok.Pos.Column != 1 {
+			t.Errorf("%q: starts at %s, want 1:1", tt.input, tok.Pos)
+		}
+		if tok.End.Line != tt.endLine || tok.End.Column != tt.endCol {
+			t.Errorf("%q: ends at %d:%d, want %d:%d", tt.input, tok.End.Line, tok.End.Column, tt.endLine, tt.endCol)
+		}
+	}
+}
+
+func TestUnicodeIdentifiers(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"função", []string{"função"}},
+		{"maçã = Usuário", []string{"maçã", "=", "Usuário"}},
+		{"_privado1 ñ2", []string{"_privado1", "ñ2"}},
+		{"变量 + переменная", []string{"变量", "+", "переменная"}},
+		// Uma letra seguida de acento combinado continua o identificador
+		{"cafe\u0301 x", []string{"cafe\u0301", "x"}},
+	}
+
+	for _, tt := range tests {
+		toks := tokens(tt.input)
+		if len(toks) != len(tt.want) {
+			t.Errorf("%q: got %v, want %q", tt.input, toks, tt.want)
+			continue
+		}
+		for i, tok := range toks {
+			if tok.Literal != tt.want[i] {
+				t.Errorf("%q: token %d = %q, want %q", tt.input, i, tok.Literal, tt.want[i])
+			}
+		}
+	}
+
+	// Palavras-chave continuam sendo reconhecidas ao lado de identificadores
+	// acentuados, e símbolos que não são letras continuam ilegais
+	if tok := tokens("fn ação")[0]; tok.Type != TokenFunction {
+		t.Errorf("fn: got %s", tok.Type)
+	}
+	for _, input := range []string{"€", "😀", "\u0301", "\xff"} {
+		if tok := single(t, input); tok.Type != TokenIllegal || tok.Literal != input {
+			t.Errorf("%q: got %s %q, want ILLEGAL", input, tok.Type, tok.Literal)
+		}
+	}
+}
+
+// As colunas contam caracteres, não bytes
+func TestUnicodeColumns(t *testing.T) {
+	toks := tokens("var ação = \"é😀\" + ñ\n  função")
+	want := []struct {
+		literal   string
+		line, col int
+	}{
+		{"var", 1, 1},
+		{"ação", 1, 5},
+		{"=", 1, 10},
+		{"é😀", 1, 12},
+		{"+", 1, 17},
+		{"ñ", 1, 19},
+		{"função", 2, 3},
+	}
+	if len(toks) != len(want) {
+		t.Fatalf("got %v, want %d tokens", toks, len(want))
+	}
+	for i, tok := range toks {
+		w := want[i]
+		if tok.Literal != w.literal || tok.Pos.Line != w.line || tok.Pos.Column != w.col {
+			t.Errorf("token %d = %q at %d:%d, want %q at %d:%d", i, tok.Literal, tok.Pos.Line, tok.Pos.Column, w.literal, w.line, w.col)
+		}
+	}
+	if end := toks[1].End; end.Column != 9 {
+		t.Errorf("ação ends at column %d, want 9", end.Column)
+	}
+}
+
+// A BOM é ignorada no início de um arquivo, sem deslocar as colunas; em
+// outro lugar é um caractere ilegal
+func TestByteOrderMark(t *testing.T) {
+	l := NewFileLexer("main.jt", bom+"var x")
+	tok := l.NextToken()
+	if tok.Type != TokenVar || tok.Pos.Column != 1 {
+		t.Errorf("got %s at column %d, want var at column 1", tok.Type, tok.Pos.Column)
+	}
+
+	toks := tokens("x " + bom)
+	if len(toks) != 2 || toks[1].Type != TokenIllegal {
+		t.Errorf("got %v, want x and an illegal BOM", toks)
+	}
+}
+
+func TestComments(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"a // comment\nb", []string{"a", "b"}},
+		{"a /* comment */ b", []string{"a", "b"}},
+		{"a /* line 1\nline 2 */ b", []string{"a", "b"}},
+		{"a /* outer /* inner */ still outer */ b", []string{"a", "b"}},
+		{"a /* /* /* three */ */ */ b", []string{"a", "b"}},
+		{"a /* // line comment inside */ b", []string{"a", "b"}},
+		{"a /**/ b /***/ c", []string{"a", "b", "c"}},
+		{"a // /* not a block\nb", []string{"a", "b"}},
+		{"a / b /= c", []string{"a", "/", "b", "/=", "c"}},
+		{"x // comment at EOF", []string{"x"}},
+	}
+
+	for _, tt := range tests {
+		toks := tokens(tt.input)
+		if len(toks) != len(tt.want) {
+			t.Errorf("%q: got %v, want %q", tt.input, toks, tt.want)
+			continue
+		}
+		for i, tok := range toks {
+			if tok.Literal != tt.want[i] {
+				t.Errorf("%q: token %d = %q, want %q", tt.input, i, tok.Literal, tt.want[i])
+			}
+		}
+	}
+}
+
+// Um comentário de bloco não fechado, inclusive um aninhado, é um erro na
+// posição em que começa
+func TestUnterminatedBlockComment(t *testing.T) {
+	for _, input := range []string{"x /* abc", "x /* a /* b */ c", "x /*/"} {
+		toks := tokens(input)
+		if len(toks) != 2 {
+			t.Errorf("%q: got %v, want x and an error", input, toks)
+			continue
+		}
+		tok := toks[1]
+		if tok.Type != TokenError || tok.Literal != "unterminated block comment" || tok.Pos.Column != 3 {
+			t.Errorf("%q: got %s %q at column %d, want unterminated block comment at column 3", input, tok.Type, tok.Literal, tok.Pos.Column)
+		}
+	}
+}
+
+func TestDocComments(t *testing.T) {
+	tests := []struct {
+		input string
+		doc   string // Doc do primeiro token que não é comentário
+	}{
+		{"/// Soma dois números\nfn", "Soma dois números"},
+		{"/// linha 1\n///  linha 2\n///\nclass", "linha 1\n linha 2\n"},
+		{"///sem espaço\nprop", "sem espaço"},
+		{"/// com CRLF\r\nfn", "com CRLF"},
+		{"   /// indentado\n   fn", "indentado"},
+		// Comentários comuns entre o doc e a declaração não o descartam
+		{"/// doc\n// comum\n/* bloco */\nfn", "doc"},
+		// Duas ou quatro barras não são documentação
+		{"// comum\nfn", ""},
+		{"//// separador\nfn", ""},
+		{"/* bloco */ fn", ""},
+	}
+
+	for _, tt := range tests {
+		toks := tokens(tt.input)
+		if len(toks) != 1 {
+			t.Errorf("%q: got %v, want one token", tt.input, toks)
+			continue
+		}
+		if toks[0].Doc != tt.doc {
+			t.Errorf("%q: Doc = %q, want %q", tt.input, toks[0].Doc, tt.doc)
+		}
+	}
+
+	// O doc vai só para o token seguinte
+	toks := tokens("/// doc\nfn f")
+	if toks[0].Doc != "doc" || toks[1].Doc != "" {
+		t.Errorf("Doc = %q, %q, want only the first token documented", toks[0].Doc, toks[1].Doc)
+	}
+}
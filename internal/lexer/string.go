@@ -0,0 +1,193 @@
+package lexer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// TemplatePart é um trecho de uma string com interpolação: um texto, com os
+// escapes já resolvidos, ou o código-fonte de uma expressão ${...}
+type TemplatePart struct {
+	Text   string
+	Expr   string
+	Pos    Position // onde a expressão começa
+	IsExpr bool
+}
+
+// startsWith indica se o input continua com s a partir do caractere atual
+func (l *Lexer) startsWith(s string) bool {
+	return l.position < len(l.input) && strings.HasPrefix(l.input[l.position:], s)
+}
+
+// readString lê uma string entre aspas e retorna um TokenString com os
+// escapes resolvidos. Se houver ${expressão}, retorna um TokenTemplate com
+// as partes. Entre aspas simples a string termina na linha; entre aspas
+// triplas pode ter várias linhas, e uma quebra logo após as aspas de
+// abertura é ignorada
+func (l *Lexer) readString() Token {
+	start := l.pos()
+
+	quote := `"`
+	if l.startsWith(`"""`) {
+		quote = `"""`
+	}
+	for range quote {
+		l.readChar()
+	}
+	if quote == `"""` && l.ch == '\n' {
+		l.readChar()
+	}
+
+	bodyStart := l.position
+	var text strings.Builder
+	var parts []TemplatePart
+	var invalid *Token
+
+	for {
+		switch {
+		case l.ch == 0 || (l.ch == '\n' && quote == `"`):
+			return Token{Type: TokenError, Literal: "unterminated string literal", Pos: start}
+		case l.startsWith(quote):
+			body := l.input[bodyStart:l.position]
+			for range quote {
+				l.readChar()
+			}
+			if invalid != nil {
+				return *invalid
+			}
+			if parts == nil {
+				return Token{Type: TokenString, Literal: text.String(), Pos: start}
+			}
+			parts = append(parts, TemplatePart{Text: text.String()})
+			return Token{Type: TokenTemplate, Literal: body, Pos: start, Parts: parts}
+		case l.ch == '\\':
+			pos := l.pos()
+			r, err := l.readEscape()
+			if err != "" && invalid == nil {
+				invalid = &Token{Type: TokenError, Literal: err, Pos: pos, End: l.pos()}
+			}
+			text.WriteRune(r)
+		case l.ch == '$' && l.peekChar() == '{':
+			parts = append(parts, TemplatePart{Text: text.String()})
+			text.Reset()
+
+			l.readChar()
+			l.readChar()
+			pos := l.pos()
+			exprStart := l.position
+			if !l.skipInterpolation(quote == `"`) {
+				return Token{Type: TokenError, Literal: "unterminated interpolation ${", Pos: start}
+			}
+			parts = append(parts, TemplatePart{Expr: l.input[exprStart:l.position], Pos: pos, IsExpr: true})
+			l.readChar() // }
+		default:
+			text.WriteRune(l.ch)
+			l.readChar()
+		}
+	}
+}
+
+// readEscape lê uma sequência de escape: \n, \t, \r, \0, \\, \", \$ ou
+// \u{código}. Retorna uma mensagem de erro se a sequência for inválida
+func (l *Lexer) readEscape() (rune, string) {
+	l.readChar() // \
+	ch := l.ch
+	if ch == 0 {
+		return 0, ""
+	}
+	l.readChar()
+
+	switch ch {
+	case 'n':
+		return '\n', ""
+	case 't':
+		return '\t', ""
+	case 'r':
+		return '\r', ""
+	case '0':
+		return 0, ""
+	case '\\', '"', '$':
+		return ch, ""
+	case 'u':
+		if l.ch != '{' {
+			return utf8.RuneError, `invalid unicode escape: expected \u{...}`
+		}
+		l.readChar()
+		start := l.position
+		for isHexDigit(l.ch) {
+			l.readChar()
+		}
+		digits := l.input[start:l.position]
+		if l.ch != '}' {
+			return utf8.RuneError, `invalid unicode escape: expected \u{...}`
+		}
+		l.readChar()
+
+		code, err := strconv.ParseUint(digits, 16, 32)
+		if err != nil || !utf8.ValidRune(rune(code)) {
+			return utf8.RuneError, fmt.Sprintf(`invalid unicode code point \u{%s}`, digits)
+		}
+		return rune(code), ""
+	default:
+		return utf8.RuneError, fmt.Sprintf("invalid escape sequence \\%c", ch)
+	}
+}
+
+// skipInterpolation avança até a chave que fecha ${, pulando chaves e
+// strings aninhadas. O caractere atual fica sobre a chave de fechamento
+func (l *Lexer) skipInterpolation(singleLine bool) bool {
+	depth := 1
+	for {
+		switch l.ch {
+		case 0:
+			return false
+		case '\n':
+			if singleLine {
+				return false
+			}
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return true
+			}
+		case '"':
+			if tok := l.readString(); tok.Type == TokenError {
+				return false
+			}
+			continue
+		case '`':
+			if tok := l.readRawString(); tok.Type == TokenError {
+				return false
+			}
+			continue
+		}
+		l.readChar()
+	}
+}
+
+// readRawString lê uma string entre crases. O conteúdo é usado como está,
+// sem escapes nem interpolação, e pode ter várias linhas
+func (l *Lexer) readRawString() Token {
+	start := l.pos()
+	l.readChar()
+
+	bodyStart := l.position
+	for l.ch != '`' {
+		if l.ch == 0 {
+			return Token{Type: TokenError, Literal: "unterminated raw string literal", Pos: start}
+		}
+		l.readChar()
+	}
+
+	body := l.input[bodyStart:l.position]
+	l.readChar()
+	return Token{Type: TokenString, Literal: body, Pos: start}
+}
+
+func isHexDigit(ch rune) bool {
+	return isDigit(ch) || 'a' <= ch && ch <= 'f' || 'A' <= ch && ch <= 'F'
+}
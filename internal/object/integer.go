@@ -1,12 +0,0 @@
-package object
-
-import "fmt"
-
-const INTEGER_OBJ = "INTEGER"
-
-type Integer struct {
-	Value int64
-}
-
-func (i *Integer) Type() ObjectType { return INTEGER_OBJ }
-func (i *Integer) Inspect() string  { return fmt.Sprintf("%d", i.Value) }
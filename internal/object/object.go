@@ -5,9 +5,12 @@ import (
 	"fmt"
 	"hash/fnv"
 	"jotlango/internal/ast"
+	"jotlango/internal/lexer"
+	"math"
+	"sort"
+	"strconv"
 	"strings"
-
-	"github.com/jotlang/jotlango/internal/ast"
+	"sync"
 )
 
 type ObjectType string
@@ -15,7 +18,8 @@ type ObjectType string
 const (
 	NULL_OBJ         = "NULL"
 	ERROR_OBJ        = "ERROR"
-	NUMBER_OBJ       = "NUMBER"
+	INTEGER_OBJ      = "INTEGER"
+	FLOAT_OBJ        = "FLOAT"
 	BOOLEAN_OBJ      = "BOOLEAN"
 	STRING_OBJ       = "STRING"
 	RETURN_VALUE_OBJ = "RETURN_VALUE"
@@ -23,6 +27,15 @@ const (
 	BUILTIN_OBJ      = "BUILTIN"
 	ARRAY_OBJ        = "ARRAY"
 	HASH_OBJ         = "HASH"
+	RANGE_OBJ        = "RANGE"
+	BREAK_OBJ        = "BREAK"
+	CONTINUE_OBJ     = "CONTINUE"
+	CLASS_OBJ        = "CLASS"
+	INSTANCE_OBJ     = "INSTANCE"
+	BOUND_METHOD_OBJ = "BOUND_METHOD"
+	INTERFACE_OBJ    = "INTERFACE"
+	SUPER_OBJ        = "SUPER"
+	MODULE_OBJ       = "MODULE"
 )
 
 type Object interface {
@@ -39,10 +52,20 @@ func (n *Null) Inspect() string  { return "null" }
 // Error representa um erro
 type Error struct {
 	Message string
+	Pos     lexer.Position // posição do nó que originou o erro
+
+	// Origin é onde o erro ocorreu dentro da biblioteca padrão, quando Pos
+	// aponta para a chamada no código do usuário
+	Origin lexer.Position
 }
 
 func (e *Error) Type() ObjectType { return ERROR_OBJ }
-func (e *Error) Inspect() string  { return "ERROR: " + e.Message }
+func (e *Error) Inspect() string {
+	if e.Pos.IsValid() {
+		return "ERROR: " + e.Pos.String() + ": " + e.Message
+	}
+	return "ERROR: " + e.Message
+}
 
 // String representa uma string
 type String struct {
@@ -52,13 +75,38 @@ type String struct {
 func (s *String) Type() ObjectType { return STRING_OBJ }
 func (s *String) Inspect() string  { return s.Value }
 
-// Number representa um número
-type Number struct {
+// Integer representa um inteiro de 64 bits. Literais sem ponto decimal
+// produzem Integer
+type Integer struct {
+	Value int64
+}
+
+func (i *Integer) Type() ObjectType { return INTEGER_OBJ }
+func (i *Integer) Inspect() string  { return strconv.FormatInt(i.Value, 10) }
+
+// Float representa um número de ponto flutuante de 64 bits
+type Float struct {
 	Value float64
 }
 
-func (n *Number) Type() ObjectType { return NUMBER_OBJ }
-func (n *Number) Inspect() string  { return fmt.Sprintf("%g", n.Value) }
+func (f *Float) Type() ObjectType { return FLOAT_OBJ }
+func (f *Float) Inspect() string  { return FormatFloat(f.Value) }
+
+// FormatFloat formata um float sem notação científica para valores usuais
+// e sempre com parte decimal, para não ser confundido com um inteiro:
+// 1000000.0, 2.5, 1e+21
+func FormatFloat(f float64) string {
+	abs := math.Abs(f)
+	if math.IsInf(f, 0) || math.IsNaN(f) || abs >= 1e21 || (abs != 0 && abs < 1e-6) {
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+
+	s := strconv.FormatFloat(f, 'f', -1, 64)
+	if !strings.Contains(s, ".") {
+		s += ".0"
+	}
+	return s
+}
 
 // Boolean representa um valor booleano
 type Boolean struct {
@@ -94,15 +142,6 @@ func (f *Function) Inspect() string {
 	return out.String()
 }
 
-// Class representa uma classe
-type Class struct {
-	Name string
-	Env  *Environment
-}
-
-func (c *Class) Type() ObjectType { return INTEGER_OBJ }
-func (c *Class) Inspect() string  { return fmt.Sprintf("class %s", c.Name) }
-
 // ReturnValue representa um valor de retorno
 type ReturnValue struct {
 	Value Object
@@ -111,10 +150,81 @@ type ReturnValue struct {
 func (rv *ReturnValue) Type() ObjectType { return RETURN_VALUE_OBJ }
 func (rv *ReturnValue) Inspect() string  { return rv.Value.Inspect() }
 
-// Environment representa um ambiente de execução
+// Break sinaliza um break que sobe pelos blocos até o laço mais próximo
+type Break struct{}
+
+func (b *Break) Type() ObjectType { return BREAK_OBJ }
+func (b *Break) Inspect() string  { return "break" }
+
+// Continue sinaliza um continue que sobe pelos blocos até o laço mais próximo
+type Continue struct{}
+
+func (c *Continue) Type() ObjectType { return CONTINUE_OBJ }
+func (c *Continue) Inspect() string  { return "continue" }
+
+// Range representa o intervalo semiaberto [Start, End) criado por a..b
+type Range struct {
+	Start int64
+	End   int64
+}
+
+func (r *Range) Type() ObjectType { return RANGE_OBJ }
+func (r *Range) Inspect() string  { return fmt.Sprintf("%d..%d", r.Start, r.End) }
+
+// Environment representa um ambiente de execução. Pode ser usado por várias
+// goroutines, como os handlers do servidor HTTP
 type Environment struct {
+	mu    sync.RWMutex
 	store map[string]Object
 	outer *Environment
+
+	// Apenas no ambiente raiz de um módulo
+	file     string
+	importer Importer
+}
+
+// Importer carrega o módulo identificado por path, importado a partir do
+// arquivo from, e retorna um *Module ou um *Error
+type Importer interface {
+	Import(path string, from string) Object
+}
+
+// NewModuleEnvironment cria o ambiente raiz de um módulo carregado de file
+func NewModuleEnvironment(file string, importer Importer) *Environment {
+	env := NewEnvironment()
+	env.file = file
+	env.importer = importer
+	return env
+}
+
+// Importer retorna o importador e o arquivo do módulo ao qual o ambiente pertence
+func (e *Environment) Importer() (Importer, string) {
+	for env := e; env != nil; env = env.outer {
+		if env.importer != nil {
+			return env.importer, env.file
+		}
+	}
+	return nil, ""
+}
+
+// GetLocal procura um nome apenas neste escopo, sem consultar os externos
+func (e *Environment) GetLocal(name string) (Object, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	obj, ok := e.store[name]
+	return obj, ok
+}
+
+// Names retorna os nomes definidos neste escopo em ordem alfabética
+func (e *Environment) Names() []string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	names := make([]string, 0, len(e.store))
+	for name := range e.store {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
 
 func NewEnvironment() *Environment {
@@ -129,7 +239,7 @@ func NewEnclosedEnvironment(outer *Environment) *Environment {
 }
 
 func (e *Environment) Get(name string) (Object, bool) {
-	obj, ok := e.store[name]
+	obj, ok := e.GetLocal(name)
 	if !ok && e.outer != nil {
 		obj, ok = e.outer.Get(name)
 	}
@@ -137,10 +247,57 @@ func (e *Environment) Get(name string) (Object, bool) {
 }
 
 func (e *Environment) Set(name string, val Object) Object {
+	e.mu.Lock()
+	defer e.mu.Unlock()
 	e.store[name] = val
 	return val
 }
 
+// Assign atualiza a variável no escopo mais próximo em que ela existe; se
+// não existir em nenhum, ela é criada no escopo atual
+func (e *Environment) Assign(name string, val Object) Object {
+	for env := e; env != nil; env = env.outer {
+		if env.replace(name, val) {
+			return val
+		}
+	}
+	return e.Set(name, val)
+}
+
+// replace altera name neste escopo, se ele existir
+func (e *Environment) replace(name string, val Object) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if _, ok := e.store[name]; !ok {
+		return false
+	}
+	e.store[name] = val
+	return true
+}
+
+// Module representa um módulo importado. Nomes iniciados por _ são privados
+type Module struct {
+	Name string
+	Path string
+	Env  *Environment
+}
+
+func (m *Module) Type() ObjectType { return MODULE_OBJ }
+func (m *Module) Inspect() string  { return "module " + m.Name }
+
+// Get retorna um nome exportado pelo módulo
+func (m *Module) Get(name string) (Object, bool) {
+	if IsPrivateName(name) {
+		return nil, false
+	}
+	return m.Env.GetLocal(name)
+}
+
+// IsPrivateName indica se um nome é privado ao módulo que o define
+func IsPrivateName(name string) bool {
+	return strings.HasPrefix(name, "_")
+}
+
 // Builtin representa uma função built-in
 type BuiltinFunction func(args ...Object) Object
 
@@ -158,16 +315,64 @@ var (
 	FALSE = &Boolean{Value: false}
 )
 
-// Array representa um array
+// Array representa um array. Elements só é acessado diretamente ao criar o
+// array; depois disso, os elementos passam pelos métodos, que podem ser
+// chamados de várias goroutines
 type Array struct {
 	Elements []Object
+
+	mu sync.RWMutex
+}
+
+// Len retorna o número de elementos
+func (a *Array) Len() int {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return len(a.Elements)
+}
+
+// Get retorna o elemento na posição i, se ela existir
+func (a *Array) Get(i int) (Object, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if i < 0 || i >= len(a.Elements) {
+		return nil, false
+	}
+	return a.Elements[i], true
+}
+
+// Set substitui o elemento na posição i, se ela existir
+func (a *Array) Set(i int, value Object) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if i < 0 || i >= len(a.Elements) {
+		return false
+	}
+	a.Elements[i] = value
+	return true
+}
+
+// Snapshot retorna uma cópia dos elementos, que pode ser percorrida enquanto
+// o array é alterado
+func (a *Array) Snapshot() []Object {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return append([]Object(nil), a.Elements...)
+}
+
+// Update substitui os elementos pelo retorno de fn, chamada com os elementos
+// atuais sem que outra goroutine possa alterá-los no meio
+func (a *Array) Update(fn func(elements []Object) []Object) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.Elements = fn(a.Elements)
 }
 
 func (a *Array) Type() ObjectType { return ARRAY_OBJ }
 func (a *Array) Inspect() string {
 	var out bytes.Buffer
 	elements := []string{}
-	for _, e := range a.Elements {
+	for _, e := range a.Snapshot() {
 		elements = append(elements, e.Inspect())
 	}
 	out.WriteString("[")
@@ -188,16 +393,77 @@ type HashPair struct {
 	Value Object
 }
 
-// Hash representa um hash
+// Hash representa um hash. Os pares são mantidos na ordem de inserção e
+// só são acessados pelos métodos, que podem ser chamados de várias goroutines
 type Hash struct {
-	Pairs map[HashKey]HashPair
+	mu    sync.RWMutex
+	pairs map[HashKey]HashPair
+	keys  []HashKey
+}
+
+func NewHash() *Hash {
+	return &Hash{pairs: make(map[HashKey]HashPair)}
+}
+
+// Get retorna o par com a chave key
+func (h *Hash) Get(key HashKey) (HashPair, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	pair, ok := h.pairs[key]
+	return pair, ok
+}
+
+// Len retorna o número de pares
+func (h *Hash) Len() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.pairs)
+}
+
+// Set insere ou substitui um par. Uma chave nova vai para o fim; uma chave
+// existente mantém sua posição
+func (h *Hash) Set(key HashKey, pair HashPair) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.pairs[key]; !ok {
+		h.keys = append(h.keys, key)
+	}
+	h.pairs[key] = pair
+}
+
+// Delete remove um par, se existir
+func (h *Hash) Delete(key HashKey) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.pairs[key]; !ok {
+		return false
+	}
+	delete(h.pairs, key)
+	for i, k := range h.keys {
+		if k == key {
+			h.keys = append(h.keys[:i], h.keys[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// Ordered retorna uma cópia dos pares na ordem de inserção
+func (h *Hash) Ordered() []HashPair {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	pairs := make([]HashPair, 0, len(h.keys))
+	for _, key := range h.keys {
+		pairs = append(pairs, h.pairs[key])
+	}
+	return pairs
 }
 
 func (h *Hash) Type() ObjectType { return HASH_OBJ }
 func (h *Hash) Inspect() string {
 	var out bytes.Buffer
 	pairs := []string{}
-	for _, pair := range h.Pairs {
+	for _, pair := range h.Ordered() {
 		pairs = append(pairs, fmt.Sprintf("%s: %s",
 			pair.Key.Inspect(), pair.Value.Inspect()))
 	}
@@ -228,104 +494,19 @@ func (i *Integer) HashKey() HashKey {
 	return HashKey{Type: i.Type(), Value: uint64(i.Value)}
 }
 
+// HashKey implementa a interface Hashable para Float. Floats com valor
+// inteiro usam a chave do Integer equivalente, então 1 e 1.0 são a mesma
+// chave, assim como 1 == 1.0
+func (f *Float) HashKey() HashKey {
+	if f.Value == math.Trunc(f.Value) && f.Value >= math.MinInt64 && f.Value < math.MaxInt64 {
+		return (&Integer{Value: int64(f.Value)}).HashKey()
+	}
+	return HashKey{Type: f.Type(), Value: math.Float64bits(f.Value)}
+}
+
 // HashKey implementa a interface Hashable para String
 func (s *String) HashKey() HashKey {
 	h := fnv.New64a()
 	h.Write([]byte(s.Value))
 	return HashKey{Type: s.Type(), Value: h.Sum64()}
 }
-
-var builtins = map[string]*Builtin{
-	"len": {
-		Fn: func(args ...Object) Object {
-			if len(args) != 1 {
-				return newError("wrong number of arguments. got=%d, want=1", len(args))
-			}
-
-			switch arg := args[0].(type) {
-			case *Array:
-				return &Number{Value: float64(len(arg.Elements))}
-			case *String:
-				return &Number{Value: float64(len(arg.Value))}
-			default:
-				return newError("argument to `len` not supported, got %s", args[0].Type())
-			}
-		},
-	},
-	"first": {
-		Fn: func(args ...Object) Object {
-			if len(args) != 1 {
-				return newError("wrong number of arguments. got=%d, want=1", len(args))
-			}
-			if args[0].Type() != ARRAY_OBJ {
-				return newError("argument to `first` must be ARRAY, got %s", args[0].Type())
-			}
-
-			arr := args[0].(*Array)
-			if len(arr.Elements) > 0 {
-				return arr.Elements[0]
-			}
-			return NULL
-		},
-	},
-	"last": {
-		Fn: func(args ...Object) Object {
-			if len(args) != 1 {
-				return newError("wrong number of arguments. got=%d, want=1", len(args))
-			}
-			if args[0].Type() != ARRAY_OBJ {
-				return newError("argument to `last` must be ARRAY, got %s", args[0].Type())
-			}
-
-			arr := args[0].(*Array)
-			length := len(arr.Elements)
-			if length > 0 {
-				return arr.Elements[length-1]
-			}
-			return NULL
-		},
-	},
-	"rest": {
-		Fn: func(args ...Object) Object {
-			if len(args) != 1 {
-				return newError("wrong number of arguments. got=%d, want=1", len(args))
-			}
-			if args[0].Type() != ARRAY_OBJ {
-				return newError("argument to `rest` must be ARRAY, got %s", args[0].Type())
-			}
-
-			arr := args[0].(*Array)
-			length := len(arr.Elements)
-			if length > 0 {
-				newElements := make([]Object, length-1)
-				copy(newElements, arr.Elements[1:length])
-				return &Array{Elements: newElements}
-			}
-			return NULL
-		},
-	},
-	"push": {
-		Fn: func(args ...Object) Object {
-			if len(args) != 2 {
-				return newError("wrong number of arguments. got=%d, want=2", len(args))
-			}
-			if args[0].Type() != ARRAY_OBJ {
-				return newError("first argument to `push` must be ARRAY, got %s", args[0].Type())
-			}
-
-			arr := args[0].(*Array)
-			length := len(arr.Elements)
-
-			newElements := make([]Object, length+1)
-			copy(newElements, arr.Elements)
-			newElements[length] = args[1]
-
-			return &Array{Elements: newElements}
-		},
-	},
-}
-
-// newError cria um novo objeto de erro
-func newError(format string, a ...interface{}) *Error {
-	return &Error{Message: fmt.Sprintf(format, a...)}
-}
@@ -1,499 +1,15 @@
 package parser
 
 import (
-	"bytes"
 	"fmt"
 	"strconv"
 	"strings"
 
 	"jotlango/internal/ast"
+	"jotlango/internal/diag"
 	"jotlango/internal/lexer"
 )
 
-// Node representa um nó na AST
-type Node interface {
-	TokenLiteral() string
-}
-
-// Statement representa uma declaração
-type Statement interface {
-	Node
-	statementNode()
-	String() string
-}
-
-// Expression representa uma expressão
-type Expression interface {
-	Node
-	expressionNode()
-	String() string
-}
-
-// Program representa o programa completo
-type Program struct {
-	Statements []Statement
-}
-
-func (p *Program) TokenLiteral() string {
-	if len(p.Statements) > 0 {
-		return p.Statements[0].TokenLiteral()
-	}
-	return ""
-}
-
-func (p *Program) String() string {
-	var out string
-	for _, s := range p.Statements {
-		out += s.String()
-	}
-	return out
-}
-
-// ExpressionStatement representa uma expressão como declaração
-type ExpressionStatement struct {
-	Token      lexer.Token
-	Expression Expression
-}
-
-func (es *ExpressionStatement) statementNode()       {}
-func (es *ExpressionStatement) TokenLiteral() string { return es.Token.Literal }
-func (es *ExpressionStatement) String() string {
-	if es.Expression != nil {
-		return es.Expression.String()
-	}
-	return ""
-}
-
-// IntegerLiteral representa um número inteiro
-type IntegerLiteral struct {
-	Token lexer.Token
-	Value int64
-}
-
-func (il *IntegerLiteral) expressionNode()      {}
-func (il *IntegerLiteral) TokenLiteral() string { return il.Token.Literal }
-func (il *IntegerLiteral) String() string       { return strconv.FormatInt(il.Value, 10) }
-
-// FloatLiteral representa um número decimal
-type FloatLiteral struct {
-	Token lexer.Token
-	Value float64
-}
-
-func (fl *FloatLiteral) expressionNode()      {}
-func (fl *FloatLiteral) TokenLiteral() string { return fl.Token.Literal }
-func (fl *FloatLiteral) String() string       { return strconv.FormatFloat(fl.Value, 'f', -1, 64) }
-
-// StringLiteral representa uma string
-type StringLiteral struct {
-	Token lexer.Token
-	Value string
-}
-
-func (sl *StringLiteral) expressionNode()      {}
-func (sl *StringLiteral) TokenLiteral() string { return sl.Token.Literal }
-func (sl *StringLiteral) String() string       { return sl.Value }
-
-// Boolean representa um valor booleano
-type Boolean struct {
-	Token lexer.Token
-	Value bool
-}
-
-func (b *Boolean) expressionNode()      {}
-func (b *Boolean) TokenLiteral() string { return b.Token.Literal }
-func (b *Boolean) String() string       { return fmt.Sprintf("%t", b.Value) }
-
-// PrefixExpression representa uma expressão prefixada
-type PrefixExpression struct {
-	Token    lexer.Token
-	Operator string
-	Right    Expression
-}
-
-func (pe *PrefixExpression) expressionNode()      {}
-func (pe *PrefixExpression) TokenLiteral() string { return pe.Token.Literal }
-func (pe *PrefixExpression) String() string {
-	var out bytes.Buffer
-	out.WriteString("(")
-	out.WriteString(pe.Operator)
-	out.WriteString(pe.Right.String())
-	out.WriteString(")")
-	return out.String()
-}
-
-// InfixExpression representa uma expressão infixada
-type InfixExpression struct {
-	Token    lexer.Token
-	Left     Expression
-	Operator string
-	Right    Expression
-}
-
-func (ie *InfixExpression) expressionNode()      {}
-func (ie *InfixExpression) TokenLiteral() string { return ie.Token.Literal }
-func (ie *InfixExpression) String() string {
-	var out bytes.Buffer
-	out.WriteString("(")
-	out.WriteString(ie.Left.String())
-	out.WriteString(" " + ie.Operator + " ")
-	out.WriteString(ie.Right.String())
-	out.WriteString(")")
-	return out.String()
-}
-
-// BlockStatement representa um bloco de código
-type BlockStatement struct {
-	Token      lexer.Token
-	Statements []Statement
-}
-
-func (bs *BlockStatement) statementNode()       {}
-func (bs *BlockStatement) TokenLiteral() string { return bs.Token.Literal }
-func (bs *BlockStatement) String() string {
-	var out bytes.Buffer
-	for _, s := range bs.Statements {
-		out.WriteString(s.String())
-	}
-	return out.String()
-}
-
-// IfExpression representa uma expressão condicional
-type IfExpression struct {
-	Token       lexer.Token
-	Condition   Expression
-	Consequence *BlockStatement
-	Alternative *BlockStatement
-}
-
-func (ie *IfExpression) expressionNode()      {}
-func (ie *IfExpression) TokenLiteral() string { return ie.Token.Literal }
-func (ie *IfExpression) String() string {
-	var out bytes.Buffer
-	out.WriteString("if")
-	out.WriteString(ie.Condition.String())
-	out.WriteString(" ")
-	out.WriteString(ie.Consequence.String())
-	if ie.Alternative != nil {
-		out.WriteString("else ")
-		out.WriteString(ie.Alternative.String())
-	}
-	return out.String()
-}
-
-// ReturnStatement representa uma declaração de retorno
-type ReturnStatement struct {
-	Token       lexer.Token
-	ReturnValue Expression
-}
-
-func (rs *ReturnStatement) statementNode()       {}
-func (rs *ReturnStatement) TokenLiteral() string { return rs.Token.Literal }
-func (rs *ReturnStatement) String() string {
-	var out bytes.Buffer
-	out.WriteString(rs.TokenLiteral() + " ")
-	if rs.ReturnValue != nil {
-		out.WriteString(rs.ReturnValue.String())
-	}
-	out.WriteString(";")
-	return out.String()
-}
-
-// VarStatement representa uma declaração de variável com tipo
-type VarStatement struct {
-	Token lexer.Token // o token 'var'
-	Name  *Identifier
-	Type  *Identifier
-	Value Expression
-}
-
-func (vs *VarStatement) statementNode()       {}
-func (vs *VarStatement) TokenLiteral() string { return vs.Token.Literal }
-func (vs *VarStatement) String() string {
-	var out bytes.Buffer
-	out.WriteString(vs.TokenLiteral() + " ")
-	out.WriteString(vs.Name.String())
-	if vs.Type != nil {
-		out.WriteString(": " + vs.Type.String())
-	}
-	if vs.Value != nil {
-		out.WriteString(" = ")
-		out.WriteString(vs.Value.String())
-	}
-	out.WriteString(";")
-	return out.String()
-}
-
-// Identifier representa um identificador
-type Identifier struct {
-	Token lexer.Token
-	Value string
-}
-
-func (i *Identifier) expressionNode()      {}
-func (i *Identifier) TokenLiteral() string { return i.Token.Literal }
-func (i *Identifier) String() string       { return i.Value }
-
-// FunctionLiteral representa uma função
-type FunctionLiteral struct {
-	Token      lexer.Token
-	Parameters []*Identifier
-	Body       *BlockStatement
-}
-
-func (fl *FunctionLiteral) expressionNode()      {}
-func (fl *FunctionLiteral) TokenLiteral() string { return fl.Token.Literal }
-func (fl *FunctionLiteral) String() string {
-	var out bytes.Buffer
-	params := []string{}
-	for _, p := range fl.Parameters {
-		params = append(params, p.String())
-	}
-	out.WriteString("fn(")
-	out.WriteString(strings.Join(params, ", "))
-	out.WriteString(") ")
-	out.WriteString(fl.Body.String())
-	return out.String()
-}
-
-// CallExpression representa uma chamada de função
-type CallExpression struct {
-	Token     lexer.Token
-	Function  Expression
-	Arguments []Expression
-}
-
-func (ce *CallExpression) expressionNode()      {}
-func (ce *CallExpression) TokenLiteral() string { return ce.Token.Literal }
-func (ce *CallExpression) String() string {
-	var out bytes.Buffer
-	args := []string{}
-	for _, a := range ce.Arguments {
-		args = append(args, a.String())
-	}
-	out.WriteString(ce.Function.String())
-	out.WriteString("(")
-	out.WriteString(strings.Join(args, ", "))
-	out.WriteString(")")
-	return out.String()
-}
-
-// ClassStatement representa uma declaração de classe
-type ClassStatement struct {
-	Token lexer.Token
-	Name  *Identifier
-	Body  *BlockStatement
-}
-
-func (cs *ClassStatement) expressionNode()      {}
-func (cs *ClassStatement) TokenLiteral() string { return cs.Token.Literal }
-func (cs *ClassStatement) String() string {
-	var out bytes.Buffer
-	out.WriteString("class ")
-	out.WriteString(cs.Name.String())
-	out.WriteString(" {")
-	out.WriteString(cs.Body.String())
-	out.WriteString("}")
-	return out.String()
-}
-
-// CallStatement representa uma chamada de função
-type CallStatement struct {
-	Token    lexer.Token
-	Function Expression
-}
-
-func (cs *CallStatement) expressionNode()      {}
-func (cs *CallStatement) TokenLiteral() string { return cs.Token.Literal }
-func (cs *CallStatement) String() string {
-	var out bytes.Buffer
-	out.WriteString("call ")
-	out.WriteString(cs.Function.String())
-	return out.String()
-}
-
-// PropertyStatement representa uma declaração de propriedade
-type PropertyStatement struct {
-	Token lexer.Token
-	Name  *Identifier
-	Type  *Identifier
-}
-
-func (ps *PropertyStatement) statementNode()       {}
-func (ps *PropertyStatement) TokenLiteral() string { return ps.Token.Literal }
-func (ps *PropertyStatement) String() string {
-	var out bytes.Buffer
-	out.WriteString(ps.TokenLiteral() + " ")
-	out.WriteString(ps.Name.String())
-	out.WriteString(": ")
-	out.WriteString(ps.Type.String())
-	out.WriteString(";")
-	return out.String()
-}
-
-// FunctionStatement representa uma declaração de função
-type FunctionStatement struct {
-	Token      lexer.Token
-	Name       *Identifier
-	Parameters []*Identifier
-	ReturnType *Identifier
-	Body       *BlockStatement
-}
-
-func (fs *FunctionStatement) statementNode()       {}
-func (fs *FunctionStatement) TokenLiteral() string { return fs.Token.Literal }
-func (fs *FunctionStatement) String() string {
-	var out bytes.Buffer
-	out.WriteString("fn ")
-	out.WriteString(fs.Name.String())
-	out.WriteString("(")
-	for i, param := range fs.Parameters {
-		out.WriteString(param.String())
-		if i < len(fs.Parameters)-1 {
-			out.WriteString(", ")
-		}
-	}
-	out.WriteString("): ")
-	out.WriteString(fs.ReturnType.String())
-	out.WriteString(" ")
-	out.WriteString(fs.Body.String())
-	return out.String()
-}
-
-// InstanceLiteral representa uma instância de classe
-type InstanceLiteral struct {
-	Token lexer.Token
-	Class Expression
-}
-
-func (il *InstanceLiteral) expressionNode()      {}
-func (il *InstanceLiteral) TokenLiteral() string { return il.Token.Literal }
-func (il *InstanceLiteral) String() string {
-	var out bytes.Buffer
-	out.WriteString("new ")
-	out.WriteString(il.Class.String())
-	return out.String()
-}
-
-// PropertyAccess representa um acesso a propriedade
-type PropertyAccess struct {
-	Token    lexer.Token
-	Object   Expression
-	Property *Identifier
-}
-
-func (pa *PropertyAccess) expressionNode()      {}
-func (pa *PropertyAccess) TokenLiteral() string { return pa.Token.Literal }
-func (pa *PropertyAccess) String() string {
-	var out bytes.Buffer
-	out.WriteString(pa.Object.String())
-	out.WriteString(".")
-	out.WriteString(pa.Property.String())
-	return out.String()
-}
-
-// NewExpression representa uma expressão de instanciação de classe
-type NewExpression struct {
-	Token     lexer.Token  // o token 'new'
-	ClassName string       // nome da classe a ser instanciada
-	Arguments []Expression // argumentos do construtor
-}
-
-func (ne *NewExpression) expressionNode()      {}
-func (ne *NewExpression) TokenLiteral() string { return ne.Token.Literal }
-func (ne *NewExpression) String() string {
-	var out bytes.Buffer
-
-	args := []string{}
-	for _, a := range ne.Arguments {
-		args = append(args, a.String())
-	}
-
-	out.WriteString("new ")
-	out.WriteString(ne.ClassName)
-	out.WriteString("(")
-	out.WriteString(strings.Join(args, ", "))
-	out.WriteString(")")
-
-	return out.String()
-}
-
-// AssignmentExpression representa uma atribuição
-type AssignmentExpression struct {
-	Token lexer.Token
-	Left  Expression
-	Value Expression
-}
-
-func (ae *AssignmentExpression) expressionNode()      {}
-func (ae *AssignmentExpression) TokenLiteral() string { return ae.Token.Literal }
-func (ae *AssignmentExpression) String() string {
-	var out bytes.Buffer
-	out.WriteString(ae.Left.String())
-	out.WriteString(" = ")
-	out.WriteString(ae.Value.String())
-	return out.String()
-}
-
-// ArrayLiteral representa um array
-type ArrayLiteral struct {
-	Token    lexer.Token
-	Elements []Expression
-}
-
-func (al *ArrayLiteral) expressionNode()      {}
-func (al *ArrayLiteral) TokenLiteral() string { return al.Token.Literal }
-func (al *ArrayLiteral) String() string {
-	var out bytes.Buffer
-	elements := []string{}
-	for _, el := range al.Elements {
-		elements = append(elements, el.String())
-	}
-	out.WriteString("[")
-	out.WriteString(strings.Join(elements, ", "))
-	out.WriteString("]")
-	return out.String()
-}
-
-// HashLiteral representa um hash
-type HashLiteral struct {
-	Token lexer.Token
-	Pairs map[Expression]Expression
-}
-
-func (hl *HashLiteral) expressionNode()      {}
-func (hl *HashLiteral) TokenLiteral() string { return hl.Token.Literal }
-func (hl *HashLiteral) String() string {
-	var out bytes.Buffer
-	pairs := []string{}
-	for key, value := range hl.Pairs {
-		pairs = append(pairs, key.String()+":"+value.String())
-	}
-	out.WriteString("{")
-	out.WriteString(strings.Join(pairs, ", "))
-	out.WriteString("}")
-	return out.String()
-}
-
-// IndexExpression representa um acesso a array ou hash
-type IndexExpression struct {
-	Token lexer.Token
-	Left  Expression
-	Index Expression
-}
-
-func (ie *IndexExpression) expressionNode()      {}
-func (ie *IndexExpression) TokenLiteral() string { return ie.Token.Literal }
-func (ie *IndexExpression) String() string {
-	var out bytes.Buffer
-	out.WriteString("(")
-	out.WriteString(ie.Left.String())
-	out.WriteString("[")
-	out.WriteString(ie.Index.String())
-	out.WriteString("])")
-	return out.String()
-}
-
 // Parser representa o analisador sintático
 type Parser struct {
 	l *lexer.Lexer
@@ -504,63 +20,114 @@ type Parser struct {
 	prefixParseFns map[lexer.TokenType]prefixParseFn
 	infixParseFns  map[lexer.TokenType]infixParseFn
 
-	errors []string
+	diagnostics []diag.Diagnostic
+	maxErrors   int
+	errorCount  int
+	panicking   bool // true entre um erro e a próxima sincronização
+
+	depth      int // delimitadores (, [ e { abertos até curToken
+	blockDepth int // valor de depth no início do bloco atual
+	loopDepth  int // laços envolvendo a declaração atual na função corrente
 }
 
+// DefaultMaxErrors é o número máximo de erros reportados antes de abortar o parsing
+const DefaultMaxErrors = 20
+
 type (
 	prefixParseFn func() ast.Expression
 	infixParseFn  func(ast.Expression) ast.Expression
 )
 
-// Precedências dos operadores
+// Precedências dos operadores, da menor para a maior. Como em Python, os
+// operadores bit a bit ficam acima das comparações: a & 1 == 0 é (a & 1) == 0
 const (
 	_ int = iota
 	LOWEST
+	ASSIGN      // = += -= *= /= %=
+	OR          // ||
+	AND         // &&
 	EQUALS      // ==
-	LESSGREATER // > or <
+	LESSGREATER // > < >= <=
+	RANGE       // a..b
+	BITOR       // |
+	BITXOR      // ^
+	BITAND      // &
+	SHIFT       // << >>
 	SUM         // +
-	PRODUCT     // *
-	PREFIX      // -X or !X
+	PRODUCT     // * / %
+	PREFIX      // -X, !X ou ~X
+	POWER       // a ** b
+	POSTFIX     // x++ ou x--
 	CALL        // myFunction(X)
 	INDEX       // array[index]
 	DOT         // obj.prop
 )
 
 var precedences = map[lexer.TokenType]int{
-	lexer.TokenEQ:       EQUALS,
-	lexer.TokenNotEQ:    EQUALS,
-	lexer.TokenLT:       LESSGREATER,
-	lexer.TokenGT:       LESSGREATER,
-	lexer.TokenPlus:     SUM,
-	lexer.TokenMinus:    SUM,
-	lexer.TokenSlash:    PRODUCT,
-	lexer.TokenAsterisk: PRODUCT,
-	lexer.TokenLParen:   CALL,
-	lexer.TokenLBracket: INDEX,
-	lexer.TokenDot:      DOT,
+	lexer.TokenAssign:         ASSIGN,
+	lexer.TokenPlusAssign:     ASSIGN,
+	lexer.TokenMinusAssign:    ASSIGN,
+	lexer.TokenAsteriskAssign: ASSIGN,
+	lexer.TokenSlashAssign:    ASSIGN,
+	lexer.TokenPercentAssign:  ASSIGN,
+	lexer.TokenOr:             OR,
+	lexer.TokenAnd:            AND,
+	lexer.TokenEQ:             EQUALS,
+	lexer.TokenNotEQ:          EQUALS,
+	lexer.TokenLT:             LESSGREATER,
+	lexer.TokenGT:             LESSGREATER,
+	lexer.TokenLTE:            LESSGREATER,
+	lexer.TokenGTE:            LESSGREATER,
+	lexer.TokenDotDot:         RANGE,
+	lexer.TokenBitOr:          BITOR,
+	lexer.TokenBitXor:         BITXOR,
+	lexer.TokenBitAnd:         BITAND,
+	lexer.TokenShl:            SHIFT,
+	lexer.TokenShr:            SHIFT,
+	lexer.TokenPlus:           SUM,
+	lexer.TokenMinus:          SUM,
+	lexer.TokenSlash:          PRODUCT,
+	lexer.TokenAsterisk:       PRODUCT,
+	lexer.TokenPercent:        PRODUCT,
+	lexer.TokenPower:          POWER,
+	lexer.TokenIncrement:      POSTFIX,
+	lexer.TokenDecrement:      POSTFIX,
+	lexer.TokenLParen:         CALL,
+	lexer.TokenLBracket:       INDEX,
+	lexer.TokenDot:            DOT,
 }
 
 // NewParser cria um novo parser
 func NewParser(l *lexer.Lexer) *Parser {
 	p := &Parser{
-		l:      l,
-		errors: []string{},
+		l:           l,
+		diagnostics: []diag.Diagnostic{},
+		maxErrors:   DefaultMaxErrors,
 	}
 
 	// Registra funções de parsing de prefixo
 	p.prefixParseFns = make(map[lexer.TokenType]prefixParseFn)
 	p.registerPrefix(lexer.TokenIdent, p.parseIdentifier)
+	// Os nomes de tipo int, float e bool também são as funções de conversão
+	p.registerPrefix(lexer.TokenTypeInt, p.parseIdentifier)
+	p.registerPrefix(lexer.TokenTypeFloat, p.parseIdentifier)
+	p.registerPrefix(lexer.TokenTypeBool, p.parseIdentifier)
 	p.registerPrefix(lexer.TokenInt, p.parseIntegerLiteral)
 	p.registerPrefix(lexer.TokenFloat, p.parseFloatLiteral)
 	p.registerPrefix(lexer.TokenString, p.parseStringLiteral)
+	p.registerPrefix(lexer.TokenTemplate, p.parseTemplateLiteral)
 	p.registerPrefix(lexer.TokenTrue, p.parseBoolean)
 	p.registerPrefix(lexer.TokenFalse, p.parseBoolean)
+	p.registerPrefix(lexer.TokenNull, p.parseNullLiteral)
 	p.registerPrefix(lexer.TokenLParen, p.parseGroupedExpression)
 	p.registerPrefix(lexer.TokenLBracket, p.parseArrayLiteral)
+	p.registerPrefix(lexer.TokenLBrace, p.parseHashLiteral)
 	p.registerPrefix(lexer.TokenBang, p.parsePrefixExpression)
 	p.registerPrefix(lexer.TokenMinus, p.parsePrefixExpression)
+	p.registerPrefix(lexer.TokenBitNot, p.parsePrefixExpression)
 	p.registerPrefix(lexer.TokenFunction, p.parseFunctionLiteral)
 	p.registerPrefix(lexer.TokenNew, p.parseNewExpression)
+	p.registerPrefix(lexer.TokenIf, p.parseIfExpression)
 
 	// Registra funções de parsing de infix
 	p.infixParseFns = make(map[lexer.TokenType]infixParseFn)
@@ -568,13 +135,29 @@ func NewParser(l *lexer.Lexer) *Parser {
 	p.registerInfix(lexer.TokenMinus, p.parseInfixExpression)
 	p.registerInfix(lexer.TokenSlash, p.parseInfixExpression)
 	p.registerInfix(lexer.TokenAsterisk, p.parseInfixExpression)
+	p.registerInfix(lexer.TokenPercent, p.parseInfixExpression)
 	p.registerInfix(lexer.TokenEQ, p.parseInfixExpression)
 	p.registerInfix(lexer.TokenNotEQ, p.parseInfixExpression)
 	p.registerInfix(lexer.TokenLT, p.parseInfixExpression)
 	p.registerInfix(lexer.TokenGT, p.parseInfixExpression)
+	p.registerInfix(lexer.TokenDotDot, p.parseInfixExpression)
+	for _, t := range []lexer.TokenType{
+		lexer.TokenLTE, lexer.TokenGTE, lexer.TokenAnd, lexer.TokenOr, lexer.TokenPower,
+		lexer.TokenBitAnd, lexer.TokenBitOr, lexer.TokenBitXor, lexer.TokenShl, lexer.TokenShr,
+	} {
+		p.registerInfix(t, p.parseInfixExpression)
+	}
+	p.registerInfix(lexer.TokenIncrement, p.parsePostfixExpression)
+	p.registerInfix(lexer.TokenDecrement, p.parsePostfixExpression)
 	p.registerInfix(lexer.TokenLParen, p.parseCallExpression)
 	p.registerInfix(lexer.TokenLBracket, p.parseIndexExpression)
 	p.registerInfix(lexer.TokenDot, p.parsePropertyExpression)
+	for _, t := range []lexer.TokenType{
+		lexer.TokenAssign, lexer.TokenPlusAssign, lexer.TokenMinusAssign,
+		lexer.TokenAsteriskAssign, lexer.TokenSlashAssign, lexer.TokenPercentAssign,
+	} {
+		p.registerInfix(t, p.parseAssignmentExpression)
+	}
 
 	// Lê dois tokens para inicializar curToken e peekToken
 	p.nextToken()
@@ -589,8 +172,8 @@ func (p *Parser) ParseProgram() *ast.Program {
 		Statements: []ast.Statement{},
 	}
 
-	for !p.curTokenIs(lexer.TokenEOF) {
-		stmt := p.parseStatement()
+	for !p.curTokenIs(lexer.TokenEOF) && !p.errorLimitReached() {
+		stmt := p.parseRecoveringStatement()
 		if stmt != nil {
 			program.Statements = append(program.Statements, stmt)
 		}
@@ -600,27 +183,122 @@ func (p *Parser) ParseProgram() *ast.Program {
 	return program
 }
 
+// SetMaxErrors define quantos erros são reportados antes de o parsing ser abortado
+func (p *Parser) SetMaxErrors(n int) {
+	p.maxErrors = n
+}
+
+// parseRecoveringStatement analisa uma declaração e, em caso de erro,
+// descarta tokens até o próximo ponto de sincronização
+func (p *Parser) parseRecoveringStatement() ast.Statement {
+	stmt := p.parseStatement()
+	if p.panicking {
+		p.synchronize()
+		p.panicking = false
+		return nil
+	}
+	return stmt
+}
+
+// synchronize avança até o fim da declaração atual: um `;`, uma quebra de
+// linha, ou logo antes de `}` ou de uma palavra-chave que inicia declaração
+// (`class`, `interface`, `fn`, `var`, `import`). Delimitadores abertos
+// dentro da declaração (um hash de várias linhas, por exemplo) são pulados
+// por inteiro, exceto quando uma palavra-chave indica que foram abandonados
+func (p *Parser) synchronize() {
+	// O erro foi no `}` que fecha o bloco atual, como em fn f() { x = }:
+	// ele fica para parseBlockStatement
+	if p.closesBlock() {
+		return
+	}
+
+	for !p.curTokenIs(lexer.TokenEOF) && !p.peekTokenIs(lexer.TokenEOF) {
+		atStatementLevel := p.depth <= p.blockDepth
+
+		if atStatementLevel && p.curTokenIs(lexer.TokenSemicolon) {
+			return
+		}
+
+		switch p.peekToken.Type {
+		case lexer.TokenRBrace:
+			if atStatementLevel {
+				return
+			}
+		case lexer.TokenClass, lexer.TokenInterface, lexer.TokenFunction, lexer.TokenVar, lexer.TokenImport:
+			p.depth = p.blockDepth
+			return
+		}
+
+		if atStatementLevel && p.peekToken.Pos.Line > p.curToken.Pos.Line {
+			return
+		}
+
+		p.nextToken()
+	}
+}
+
 // parseStatement analisa uma declaração
 func (p *Parser) parseStatement() ast.Statement {
+	// As funções de parsing retornam ponteiros nil em caso de erro, que não
+	// podem vazar como interfaces não-nil
 	switch p.curToken.Type {
 	case lexer.TokenClass:
-		return p.parseClassStatement()
+		if stmt := p.parseClassStatement(); stmt != nil {
+			return stmt
+		}
+	case lexer.TokenInterface:
+		if stmt := p.parseInterfaceStatement(); stmt != nil {
+			return stmt
+		}
+	case lexer.TokenImport:
+		if stmt := p.parseImportStatement(); stmt != nil {
+			return stmt
+		}
 	case lexer.TokenVar:
-		return p.parseVarStatement()
+		if stmt := p.parseVarStatement(); stmt != nil {
+			return stmt
+		}
 	case lexer.TokenReturn:
-		return p.parseReturnStatement()
+		if stmt := p.parseReturnStatement(); stmt != nil {
+			return stmt
+		}
 	case lexer.TokenFunction:
-		return p.parseFunctionStatement()
+		if stmt := p.parseFunctionStatement(); stmt != nil {
+			return stmt
+		}
 	case lexer.TokenPrint:
-		return p.parsePrintStatement()
+		if stmt := p.parsePrintStatement(); stmt != nil {
+			return stmt
+		}
+	case lexer.TokenWhile:
+		if stmt := p.parseWhileStatement(); stmt != nil {
+			return stmt
+		}
+	case lexer.TokenFor:
+		if stmt := p.parseForStatement(); stmt != nil {
+			return stmt
+		}
+	case lexer.TokenBreak, lexer.TokenContinue:
+		return p.parseLoopControlStatement()
+	case lexer.TokenProp:
+		if stmt := p.parsePropertyStatement(); stmt != nil {
+			return stmt
+		}
+	case lexer.TokenCall:
+		if stmt := p.parseCallStatement(); stmt != nil {
+			return stmt
+		}
 	default:
-		return p.parseExpressionStatement()
+		if stmt := p.parseExpressionStatement(); stmt != nil {
+			return stmt
+		}
 	}
+	return nil
 }
 
 // parseClassStatement analisa uma declaração de classe
 func (p *Parser) parseClassStatement() *ast.ClassStatement {
-	stmt := &ast.ClassStatement{Token: p.curToken}
+	stmt := &ast.ClassStatement{Token: p.curToken, Doc: p.curToken.Doc}
 
 	if !p.expectPeek(lexer.TokenIdent) {
 		return nil
@@ -628,6 +306,24 @@ func (p *Parser) parseClassStatement() *ast.ClassStatement {
 
 	stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
 
+	if p.peekTokenIs(lexer.TokenColon) {
+		p.nextToken()
+		for {
+			if !p.expectPeek(lexer.TokenIdent) {
+				return nil
+			}
+			base := p.parseQualifiedName()
+			if base == nil {
+				return nil
+			}
+			stmt.Bases = append(stmt.Bases, base)
+			if !p.peekTokenIs(lexer.TokenComma) {
+				break
+			}
+			p.nextToken()
+		}
+	}
+
 	if !p.expectPeek(lexer.TokenLBrace) {
 		return nil
 	}
@@ -637,6 +333,84 @@ func (p *Parser) parseClassStatement() *ast.ClassStatement {
 	return stmt
 }
 
+// parseInterfaceStatement analisa uma interface: uma lista de assinaturas
+// de métodos que as classes que a declaram devem implementar
+func (p *Parser) parseInterfaceStatement() *ast.InterfaceStatement {
+	stmt := &ast.InterfaceStatement{Token: p.curToken, Doc: p.curToken.Doc}
+
+	if !p.expectPeek(lexer.TokenIdent) {
+		return nil
+	}
+
+	stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if !p.expectPeek(lexer.TokenLBrace) {
+		return nil
+	}
+
+	for !p.peekTokenIs(lexer.TokenRBrace) {
+		if !p.expectPeek(lexer.TokenFunction) {
+			return nil
+		}
+
+		method := &ast.MethodSignature{Token: p.curToken, Doc: p.curToken.Doc}
+
+		if !p.expectPeek(lexer.TokenIdent) {
+			return nil
+		}
+		method.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+		if !p.expectPeek(lexer.TokenLParen) {
+			return nil
+		}
+		if method.Parameters = p.parseFunctionParameters(); method.Parameters == nil {
+			return nil
+		}
+
+		if p.peekTokenIs(lexer.TokenColon) {
+			p.nextToken()
+			if method.ReturnType = p.parseTypeAnnotation(); method.ReturnType == nil {
+				return nil
+			}
+		}
+
+		if p.peekTokenIs(lexer.TokenSemicolon) {
+			p.nextToken()
+		}
+
+		stmt.Methods = append(stmt.Methods, method)
+	}
+
+	p.nextToken()
+
+	return stmt
+}
+
+// parseImportStatement analisa `import "caminho"` com alias opcional
+func (p *Parser) parseImportStatement() *ast.ImportStatement {
+	stmt := &ast.ImportStatement{Token: p.curToken}
+
+	if !p.expectPeek(lexer.TokenString) {
+		return nil
+	}
+
+	stmt.Path = p.curToken.Literal
+
+	if p.peekTokenIs(lexer.TokenAs) {
+		p.nextToken()
+		if !p.expectPeek(lexer.TokenIdent) {
+			return nil
+		}
+		stmt.Alias = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	}
+
+	if p.peekTokenIs(lexer.TokenSemicolon) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
 // parseVarStatement analisa uma declaração de variável
 func (p *Parser) parseVarStatement() *ast.VarStatement {
 	stmt := &ast.VarStatement{Token: p.curToken}
@@ -664,7 +438,7 @@ func (p *Parser) parseVarStatement() *ast.VarStatement {
 
 // parseFunctionStatement analisa uma declaração de função
 func (p *Parser) parseFunctionStatement() *ast.FunctionStatement {
-	stmt := &ast.FunctionStatement{Token: p.curToken}
+	stmt := &ast.FunctionStatement{Token: p.curToken, Doc: p.curToken.Doc}
 
 	if !p.expectPeek(lexer.TokenIdent) {
 		return nil
@@ -678,18 +452,106 @@ func (p *Parser) parseFunctionStatement() *ast.FunctionStatement {
 
 	stmt.Parameters = p.parseFunctionParameters()
 
+	if p.peekTokenIs(lexer.TokenColon) {
+		p.nextToken()
+		stmt.ReturnType = p.parseTypeAnnotation()
+		if stmt.ReturnType == nil {
+			return nil
+		}
+	}
+
 	if !p.expectPeek(lexer.TokenLBrace) {
 		return nil
 	}
 
-	stmt.Body = p.parseBlockStatement()
+	stmt.Body = p.parseFunctionBody()
+
+	return stmt
+}
+
+// parseWhileStatement analisa um laço while
+func (p *Parser) parseWhileStatement() *ast.WhileStatement {
+	stmt := &ast.WhileStatement{Token: p.curToken}
+
+	p.nextToken()
+	stmt.Condition = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(lexer.TokenLBrace) {
+		return nil
+	}
+
+	stmt.Body = p.parseLoopBody()
+
+	return stmt
+}
+
+// parseForStatement analisa um laço for-in com uma ou duas variáveis
+func (p *Parser) parseForStatement() *ast.ForStatement {
+	stmt := &ast.ForStatement{Token: p.curToken}
+
+	if !p.expectPeek(lexer.TokenIdent) {
+		return nil
+	}
+
+	stmt.Value = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if p.peekTokenIs(lexer.TokenComma) {
+		p.nextToken()
+		if !p.expectPeek(lexer.TokenIdent) {
+			return nil
+		}
+		stmt.Key = stmt.Value
+		stmt.Value = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	}
+
+	if !p.expectPeek(lexer.TokenIn) {
+		return nil
+	}
+
+	p.nextToken()
+	stmt.Iterable = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(lexer.TokenLBrace) {
+		return nil
+	}
+
+	stmt.Body = p.parseLoopBody()
 
 	return stmt
 }
 
+// parseLoopBody analisa o corpo de um laço, onde break e continue são válidos
+func (p *Parser) parseLoopBody() *ast.BlockStatement {
+	p.loopDepth++
+	defer func() { p.loopDepth-- }()
+
+	return p.parseBlockStatement()
+}
+
+// parseLoopControlStatement analisa break e continue
+func (p *Parser) parseLoopControlStatement() ast.Statement {
+	tok := p.curToken
+
+	if p.loopDepth == 0 {
+		p.report(diag.Errorf(diag.CodeOutsideLoop, diag.TokenSpan(tok),
+			"%s outside of a loop", tok.Literal))
+		return nil
+	}
+
+	if p.peekTokenIs(lexer.TokenSemicolon) {
+		p.nextToken()
+	}
+
+	if tok.Type == lexer.TokenBreak {
+		return &ast.BreakStatement{Token: tok}
+	}
+	return &ast.ContinueStatement{Token: tok}
+}
+
 // parsePrintStatement analisa uma declaração print
-func (p *Parser) parsePrintStatement() *ast.CallExpression {
-	stmt := &ast.CallExpression{
+func (p *Parser) parsePrintStatement() *ast.ExpressionStatement {
+	stmt := &ast.ExpressionStatement{Token: p.curToken}
+	call := &ast.CallExpression{
 		Token:    p.curToken,
 		Function: &ast.Identifier{Token: p.curToken, Value: "print"},
 	}
@@ -698,11 +560,11 @@ func (p *Parser) parsePrintStatement() *ast.CallExpression {
 		return nil
 	}
 
-	p.nextToken()
-	stmt.Arguments = []ast.Expression{p.parseExpression(LOWEST)}
+	call.Arguments = p.parseExpressionList(lexer.TokenRParen)
+	stmt.Expression = call
 
-	if !p.expectPeek(lexer.TokenRParen) {
-		return nil
+	if p.peekTokenIs(lexer.TokenSemicolon) {
+		p.nextToken()
 	}
 
 	return stmt
@@ -742,8 +604,8 @@ func (p *Parser) parseIntegerLiteral() ast.Expression {
 
 	value, err := strconv.ParseInt(p.curToken.Literal, 0, 64)
 	if err != nil {
-		msg := fmt.Sprintf("could not parse %q as integer", p.curToken.Literal)
-		p.errors = append(p.errors, msg)
+		p.report(diag.Errorf(diag.CodeInvalidNumber, diag.TokenSpan(p.curToken),
+			"could not parse %q as integer", p.curToken.Literal))
 		return nil
 	}
 
@@ -758,8 +620,8 @@ func (p *Parser) parseFloatLiteral() ast.Expression {
 
 	value, err := strconv.ParseFloat(p.curToken.Literal, 64)
 	if err != nil {
-		msg := fmt.Sprintf("could not parse %q as float", p.curToken.Literal)
-		p.errors = append(p.errors, msg)
+		p.report(diag.Errorf(diag.CodeInvalidNumber, diag.TokenSpan(p.curToken),
+			"could not parse %q as float", p.curToken.Literal))
 		return nil
 	}
 
@@ -773,11 +635,62 @@ func (p *Parser) parseStringLiteral() ast.Expression {
 	return &ast.StringLiteral{Token: p.curToken, Value: p.curToken.Literal}
 }
 
+// parseTemplateLiteral analisa uma string com interpolação. O lexer separa
+// os trechos; cada expressão ${...} é analisada por um parser próprio, com
+// as posições do arquivo original
+func (p *Parser) parseTemplateLiteral() ast.Expression {
+	lit := &ast.TemplateLiteral{Token: p.curToken}
+
+	for _, part := range p.curToken.Parts {
+		if !part.IsExpr {
+			if part.Text != "" {
+				lit.Parts = append(lit.Parts, &ast.StringLiteral{Token: p.curToken, Value: part.Text})
+			}
+			continue
+		}
+
+		expr := p.parseInterpolation(part)
+		if expr == nil {
+			return nil
+		}
+		lit.Parts = append(lit.Parts, expr)
+	}
+
+	return lit
+}
+
+func (p *Parser) parseInterpolation(part lexer.TemplatePart) ast.Expression {
+	sub := NewParser(lexer.NewLexerAt(part.Pos, part.Expr))
+	if sub.curTokenIs(lexer.TokenEOF) {
+		p.report(diag.Errorf(diag.CodeMalformedToken, diag.PosSpan(part.Pos), "empty interpolation ${}"))
+		return nil
+	}
+
+	expr := sub.parseExpression(LOWEST)
+	if expr != nil && !sub.peekTokenIs(lexer.TokenEOF) {
+		sub.report(diag.Errorf(diag.CodeUnexpectedToken, diag.TokenSpan(sub.peekToken),
+			"unexpected %s in interpolation", sub.peekToken.Type))
+	}
+
+	if len(sub.diagnostics) > 0 {
+		for _, d := range sub.diagnostics {
+			p.report(d)
+		}
+		return nil
+	}
+	return expr
+}
+
 // parseBoolean analisa um literal booleano
 func (p *Parser) parseBoolean() ast.Expression {
 	return &ast.Boolean{Token: p.curToken, Value: p.curTokenIs(lexer.TokenTrue)}
 }
 
+// parseNullLiteral analisa o literal null
+func (p *Parser) parseNullLiteral() ast.Expression {
+	return &ast.NullLiteral{Token: p.curToken}
+}
+
 // parseArrayLiteral analisa um literal array
 func (p *Parser) parseArrayLiteral() ast.Expression {
 	array := &ast.ArrayLiteral{Token: p.curToken}
@@ -785,6 +698,80 @@ func (p *Parser) parseArrayLiteral() ast.Expression {
 	return array
 }
 
+// parseIfExpression analisa uma expressão condicional, incluindo cadeias else if
+func (p *Parser) parseIfExpression() ast.Expression {
+	expression := &ast.IfExpression{Token: p.curToken}
+
+	p.nextToken()
+	expression.Condition = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(lexer.TokenLBrace) {
+		return nil
+	}
+
+	expression.Consequence = p.parseBlockStatement()
+
+	if !p.peekTokenIs(lexer.TokenElse) {
+		return expression
+	}
+
+	p.nextToken()
+
+	// else if é representado como um bloco contendo outra IfExpression
+	if p.peekTokenIs(lexer.TokenIf) {
+		p.nextToken()
+		alternative := &ast.BlockStatement{Token: p.curToken}
+		nested := p.parseIfExpression()
+		if nested == nil {
+			return nil
+		}
+		alternative.Statements = []ast.Statement{
+			&ast.ExpressionStatement{Token: alternative.Token, Expression: nested},
+		}
+		expression.Alternative = alternative
+		return expression
+	}
+
+	if !p.expectPeek(lexer.TokenLBrace) {
+		return nil
+	}
+
+	expression.Alternative = p.parseBlockStatement()
+
+	return expression
+}
+
+// parseHashLiteral analisa um literal hash no formato {chave: valor, ...}
+func (p *Parser) parseHashLiteral() ast.Expression {
+	hash := &ast.HashLiteral{Token: p.curToken}
+	hash.Pairs = make(map[ast.Expression]ast.Expression)
+
+	for !p.peekTokenIs(lexer.TokenRBrace) {
+		p.nextToken()
+		key := p.parseExpression(LOWEST)
+
+		if !p.expectPeek(lexer.TokenColon) {
+			return nil
+		}
+
+		p.nextToken()
+		value := p.parseExpression(LOWEST)
+
+		hash.Pairs[key] = value
+		hash.Keys = append(hash.Keys, key)
+
+		if !p.peekTokenIs(lexer.TokenRBrace) && !p.expectPeek(lexer.TokenComma) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(lexer.TokenRBrace) {
+		return nil
+	}
+
+	return hash
+}
+
 // parseExpressionList analisa uma lista de expressões
 func (p *Parser) parseExpressionList(end lexer.TokenType) []ast.Expression {
 	list := []ast.Expression{}
@@ -846,12 +833,30 @@ func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
 	}
 
 	precedence := p.curPrecedence()
+	if p.curTokenIs(lexer.TokenPower) {
+		// ** é associativo à direita: 2 ** 3 ** 2 é 2 ** (3 ** 2)
+		precedence--
+	}
 	p.nextToken()
 	expression.Right = p.parseExpression(precedence)
 
 	return expression
 }
 
+// parsePostfixExpression analisa x++ ou x--
+func (p *Parser) parsePostfixExpression(left ast.Expression) ast.Expression {
+	if left == nil {
+		// O operando já falhou e foi reportado
+		return nil
+	}
+	if !isAssignable(left) {
+		p.report(diag.Errorf(diag.CodeInvalidAssign, diag.TokenSpan(p.curToken),
+			"cannot apply %s to %s", p.curToken.Literal, left.String()))
+		return nil
+	}
+	return &ast.PostfixExpression{Token: p.curToken, Operator: p.curToken.Literal, Left: left}
+}
+
 // parseCallExpression analisa uma expressão de chamada
 func (p *Parser) parseCallExpression(function ast.Expression) ast.Expression {
 	exp := &ast.CallExpression{Token: p.curToken, Function: function}
@@ -859,18 +864,35 @@ func (p *Parser) parseCallExpression(function ast.Expression) ast.Expression {
 	return exp
 }
 
-// parseIndexExpression analisa uma expressão de índice
+// parseIndexExpression analisa uma expressão de índice, arr[i], ou uma
+// fatia, arr[inicio:fim], em que os dois limites são opcionais
 func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
-	exp := &ast.IndexExpression{Token: p.curToken, Left: left}
+	token := p.curToken
 
-	p.nextToken()
-	exp.Index = p.parseExpression(LOWEST)
+	var index ast.Expression
+	if !p.peekTokenIs(lexer.TokenColon) {
+		p.nextToken()
+		index = p.parseExpression(LOWEST)
+	}
+
+	if p.peekTokenIs(lexer.TokenColon) {
+		p.nextToken()
+		exp := &ast.SliceExpression{Token: token, Left: left, Start: index}
+		if !p.peekTokenIs(lexer.TokenRBracket) {
+			p.nextToken()
+			exp.End = p.parseExpression(LOWEST)
+		}
+		if !p.expectPeek(lexer.TokenRBracket) {
+			return nil
+		}
+		return exp
+	}
 
 	if !p.expectPeek(lexer.TokenRBracket) {
 		return nil
 	}
 
-	return exp
+	return &ast.IndexExpression{Token: token, Left: left, Index: index}
 }
 
 // parsePropertyExpression analisa uma expressão de propriedade
@@ -880,13 +902,51 @@ func (p *Parser) parsePropertyExpression(object ast.Expression) ast.Expression {
 		Object: object,
 	}
 
-	p.nextToken()
+	if !p.expectPeek(lexer.TokenIdent) {
+		return nil
+	}
 
 	exp.Property = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
 
 	return exp
 }
 
+// parseAssignmentExpression analisa uma atribuição a variável ou propriedade,
+// simples ou composta (+=, -=, *=, /=, %=). A atribuição é associativa à
+// direita: a = b = c
+func (p *Parser) parseAssignmentExpression(left ast.Expression) ast.Expression {
+	if left == nil {
+		// O alvo já falhou e foi reportado
+		return nil
+	}
+
+	exp := &ast.AssignmentExpression{
+		Token:    p.curToken,
+		Operator: strings.TrimSuffix(p.curToken.Literal, "="),
+		Left:     left,
+	}
+
+	if !isAssignable(left) {
+		p.report(diag.Errorf(diag.CodeInvalidAssign, diag.TokenSpan(p.curToken),
+			"cannot assign to %s", left.String()))
+		return nil
+	}
+
+	p.nextToken()
+	exp.Value = p.parseExpression(ASSIGN - 1)
+
+	return exp
+}
+
+// isAssignable indica se a expressão pode receber uma atribuição
+func isAssignable(exp ast.Expression) bool {
+	switch exp.(type) {
+	case *ast.Identifier, *ast.PropertyExpression, *ast.IndexExpression, *ast.SliceExpression:
+		return true
+	}
+	return false
+}
+
 // parseNewExpression analisa uma expressão new
 func (p *Parser) parseNewExpression() ast.Expression {
 	exp := &ast.NewExpression{Token: p.curToken}
@@ -895,22 +955,236 @@ func (p *Parser) parseNewExpression() ast.Expression {
 		return nil
 	}
 
-	exp.Class = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
-
-	if !p.expectPeek(lexer.TokenLParen) {
+	exp.Class = p.parseQualifiedName()
+	if exp.Class == nil {
 		return nil
 	}
 
+	// Os parênteses são opcionais quando o construtor não recebe argumentos
+	if !p.peekTokenIs(lexer.TokenLParen) {
+		exp.Arguments = []ast.Expression{}
+		return exp
+	}
+
+	p.nextToken()
 	exp.Arguments = p.parseExpressionList(lexer.TokenRParen)
 
 	return exp
 }
 
+// parseQualifiedName analisa um nome a partir do identificador atual,
+// aceitando nomes qualificados de módulos: http.Server
+func (p *Parser) parseQualifiedName() ast.Expression {
+	var name ast.Expression = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	for p.peekTokenIs(lexer.TokenDot) {
+		p.nextToken()
+		dot := p.curToken
+		if !p.expectPeek(lexer.TokenIdent) {
+			return nil
+		}
+		name = &ast.PropertyExpression{
+			Token:    dot,
+			Object:   name,
+			Property: &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal},
+		}
+	}
+
+	return name
+}
+
+// parseCallStatement analisa `call obj.Metodo(args)`
+func (p *Parser) parseCallStatement() *ast.CallStatement {
+	stmt := &ast.CallStatement{Token: p.curToken}
+
+	p.nextToken()
+
+	call, ok := p.parseExpression(LOWEST).(*ast.CallExpression)
+	if !ok {
+		if !p.panicking {
+			p.report(diag.Errorf(diag.CodeUnexpectedToken, diag.TokenSpan(stmt.Token),
+				"call must be followed by a function call"))
+		}
+		return nil
+	}
+
+	stmt.Function = call.Function
+	stmt.Arguments = call.Arguments
+
+	if p.peekTokenIs(lexer.TokenSemicolon) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+// parsePropertyStatement analisa uma declaração de propriedade em qualquer
+// das formas aceitas: `prop Nome`, `prop Nome: tipo` ou `prop tipo Nome`,
+// opcionalmente seguida de `= valorPadrao`
+func (p *Parser) parsePropertyStatement() *ast.PropertyStatement {
+	stmt := &ast.PropertyStatement{Token: p.curToken, Doc: p.curToken.Doc}
+
+	if !p.peekIsTypeStart() {
+		p.peekError(lexer.TokenIdent)
+		return nil
+	}
+
+	p.nextToken()
+
+	if p.curTokenIs(lexer.TokenIdent) && !p.peekIsTypeStart() &&
+		!p.peekTokenIs(lexer.TokenLT) && !p.peekTokenIs(lexer.TokenLBracket) && !p.peekTokenIs(lexer.TokenDot) {
+		stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+		if p.peekTokenIs(lexer.TokenColon) {
+			p.nextToken()
+			if stmt.Type = p.parseTypeAnnotation(); stmt.Type == nil {
+				return nil
+			}
+		}
+	} else {
+		if stmt.Type = p.parseTypeRest(); stmt.Type == nil {
+			return nil
+		}
+		if !p.expectPeek(lexer.TokenIdent) {
+			return nil
+		}
+		stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	}
+
+	if p.peekTokenIs(lexer.TokenAssign) {
+		p.nextToken()
+		p.nextToken()
+		stmt.Value = p.parseExpression(LOWEST)
+	}
+
+	if p.peekTokenIs(lexer.TokenSemicolon) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+// expectTypeArgumentsEnd consome o > que fecha uma lista de tipos. O lexer
+// lê >> e >= como um só token; em Map<string, List<int>> o token é dividido
+// e o restante fica como próximo token
+func (p *Parser) expectTypeArgumentsEnd() bool {
+	rest := map[lexer.TokenType]lexer.TokenType{lexer.TokenShr: lexer.TokenGT, lexer.TokenGTE: lexer.TokenAssign}
+	if restType, ok := rest[p.peekToken.Type]; ok {
+		gt := p.peekToken
+		gt.Type, gt.Literal = lexer.TokenGT, ">"
+		p.peekToken.Type, p.peekToken.Literal = restType, p.peekToken.Literal[1:]
+		p.peekToken.Pos.Column++
+		gt.End = p.peekToken.Pos
+		p.curToken = gt
+		return true
+	}
+	return p.expectPeek(lexer.TokenGT)
+}
+
+// peekIsTypeStart indica se o próximo token pode iniciar uma anotação de tipo
+func (p *Parser) peekIsTypeStart() bool {
+	switch p.peekToken.Type {
+	case lexer.TokenIdent, lexer.TokenTypeInt, lexer.TokenTypeFloat,
+		lexer.TokenTypeString, lexer.TokenTypeBool, lexer.TokenVoid:
+		return true
+	}
+	return false
+}
+
+// parseTypeAnnotation analisa o tipo que começa no próximo token. Os tipos
+// ainda não são verificados; são guardados como texto em um Identifier
+func (p *Parser) parseTypeAnnotation() *ast.Identifier {
+	if !p.peekIsTypeStart() {
+		p.report(diag.Errorf(diag.CodeUnexpectedToken, diag.TokenSpan(p.peekToken),
+			"expected type, got %s instead", p.peekToken.Type))
+		return nil
+	}
+
+	p.nextToken()
+	return p.parseTypeRest()
+}
+
+// parseTypeRest analisa um tipo cujo primeiro token é curToken, incluindo
+// nomes qualificados (http.Request), genéricos (map<string, int>) e
+// mapas/listas no estilo map[string]int ou []string
+func (p *Parser) parseTypeRest() *ast.Identifier {
+	typ := &ast.Identifier{Token: p.curToken}
+	name := p.curToken.Literal
+
+	for {
+		switch {
+		case p.peekTokenIs(lexer.TokenDot):
+			p.nextToken()
+			if !p.expectPeek(lexer.TokenIdent) {
+				return nil
+			}
+			name += "." + p.curToken.Literal
+		case p.peekTokenIs(lexer.TokenLT):
+			p.nextToken()
+			name += "<"
+			for {
+				inner := p.parseTypeAnnotation()
+				if inner == nil {
+					return nil
+				}
+				name += inner.Value
+				if !p.peekTokenIs(lexer.TokenComma) {
+					break
+				}
+				p.nextToken()
+				name += ", "
+			}
+			if !p.expectTypeArgumentsEnd() {
+				return nil
+			}
+			name += ">"
+		case p.peekTokenIs(lexer.TokenLBracket):
+			p.nextToken()
+			name += "["
+			if !p.peekTokenIs(lexer.TokenRBracket) {
+				inner := p.parseTypeAnnotation()
+				if inner == nil {
+					return nil
+				}
+				name += inner.Value
+			}
+			if !p.expectPeek(lexer.TokenRBracket) {
+				return nil
+			}
+			name += "]"
+			if p.peekIsTypeStart() {
+				inner := p.parseTypeAnnotation()
+				if inner == nil {
+					return nil
+				}
+				name += inner.Value
+			}
+		default:
+			typ.Value = name
+			return typ
+		}
+	}
+}
+
 // Funções auxiliares
 
 func (p *Parser) nextToken() {
 	p.curToken = p.peekToken
 	p.peekToken = p.l.NextToken()
+
+	switch p.curToken.Type {
+	case lexer.TokenLParen, lexer.TokenLBracket, lexer.TokenLBrace:
+		p.depth++
+	case lexer.TokenRParen, lexer.TokenRBracket, lexer.TokenRBrace:
+		if p.depth > 0 {
+			p.depth--
+		}
+	}
+}
+
+// closesBlock indica se curToken é o `}` que fecha o bloco atual
+func (p *Parser) closesBlock() bool {
+	return p.curTokenIs(lexer.TokenRBrace) && p.depth < p.blockDepth
 }
 
 func (p *Parser) curTokenIs(t lexer.TokenType) bool {
@@ -930,14 +1204,62 @@ func (p *Parser) expectPeek(t lexer.TokenType) bool {
 	return false
 }
 
+// Errors retorna os erros de parsing formatados como file:line:col: mensagem
 func (p *Parser) Errors() []string {
-	return p.errors
+	errors := []string{}
+	for _, d := range p.diagnostics {
+		if d.Severity == diag.SeverityError {
+			errors = append(errors, d.String())
+		}
+	}
+	return errors
+}
+
+// Diagnostics retorna os diagnósticos estruturados emitidos pelo lexer e pelo parser
+func (p *Parser) Diagnostics() []diag.Diagnostic {
+	return p.diagnostics
+}
+
+// report registra um diagnóstico. Erros emitidos enquanto o parser ainda não
+// se sincronizou são consequência do primeiro e por isso descartados
+func (p *Parser) report(d diag.Diagnostic) {
+	if d.Severity == diag.SeverityError {
+		if p.panicking || p.errorLimitReached() {
+			return
+		}
+		p.panicking = true
+		p.errorCount++
+
+		if p.errorLimitReached() {
+			d.Related = append(d.Related, diag.Note{
+				Message: fmt.Sprintf("too many errors (%d), parsing stopped", p.errorCount),
+				Span:    d.Span,
+			})
+		}
+	}
+	p.diagnostics = append(p.diagnostics, d)
+}
+
+func (p *Parser) errorLimitReached() bool {
+	return p.maxErrors > 0 && p.errorCount >= p.maxErrors
 }
 
 func (p *Parser) peekError(t lexer.TokenType) {
-	msg := fmt.Sprintf("expected next token to be %s, got %s instead",
-		t, p.peekToken.Type)
-	p.errors = append(p.errors, msg)
+	d := diag.Errorf(diag.CodeUnexpectedToken, diag.TokenSpan(p.peekToken),
+		"expected next token to be %s, got %s instead", t, p.peekToken.Type)
+
+	// Delimitadores ausentes podem ser inseridos logo após o token atual
+	switch t {
+	case lexer.TokenRParen, lexer.TokenRBracket, lexer.TokenRBrace, lexer.TokenLBrace, lexer.TokenColon:
+		end := diag.TokenSpan(p.curToken).End
+		d.Fix = &diag.Fix{
+			Message:     fmt.Sprintf("insert `%s`", t),
+			Span:        diag.PosSpan(end),
+			Replacement: string(t),
+		}
+	}
+
+	p.report(d)
 }
 
 func (p *Parser) registerPrefix(tokenType lexer.TokenType, fn prefixParseFn) {
@@ -949,8 +1271,17 @@ func (p *Parser) registerInfix(tokenType lexer.TokenType, fn infixParseFn) {
 }
 
 func (p *Parser) noPrefixParseFnError(t lexer.TokenType) {
-	msg := fmt.Sprintf("no prefix parse function for %s found", t)
-	p.errors = append(p.errors, msg)
+	if t == lexer.TokenError {
+		p.report(diag.Errorf(diag.CodeMalformedToken, diag.TokenSpan(p.curToken), "%s", p.curToken.Literal))
+		return
+	}
+	if t == lexer.TokenIllegal {
+		p.report(diag.Errorf(diag.CodeIllegalChar, diag.TokenSpan(p.curToken),
+			"illegal character %q", p.curToken.Literal))
+		return
+	}
+	p.report(diag.Errorf(diag.CodeNoPrefixParse, diag.TokenSpan(p.curToken),
+		"no prefix parse function for %s found", t))
 }
 
 func (p *Parser) peekPrecedence() int {
@@ -973,19 +1304,51 @@ func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 	block := &ast.BlockStatement{Token: p.curToken}
 	block.Statements = []ast.Statement{}
 
+	outerBlockDepth := p.blockDepth
+	p.blockDepth = p.depth
+	defer func() { p.blockDepth = outerBlockDepth }()
+
+	// Um bloco dentro de uma expressão com erro, como em f(1 +, fn() { a }),
+	// não pode encerrar a recuperação da declaração de fora, que tem filhos
+	// nil e precisa ser descartada
+	if p.panicking {
+		defer func() { p.panicking = true }()
+	}
+
 	p.nextToken()
 
-	for !p.curTokenIs(lexer.TokenRBrace) && !p.curTokenIs(lexer.TokenEOF) {
-		stmt := p.parseStatement()
+	for !p.curTokenIs(lexer.TokenRBrace) && !p.curTokenIs(lexer.TokenEOF) && !p.errorLimitReached() {
+		stmt := p.parseRecoveringStatement()
 		if stmt != nil {
 			block.Statements = append(block.Statements, stmt)
 		}
+		if p.closesBlock() {
+			break
+		}
 		p.nextToken()
 	}
 
+	if p.curTokenIs(lexer.TokenEOF) {
+		d := diag.Errorf(diag.CodeUnclosedBlock, diag.TokenSpan(p.curToken),
+			"unexpected end of file, expected }")
+		d.Related = []diag.Note{{Message: "block opened here", Span: diag.TokenSpan(block.Token)}}
+		d.Fix = &diag.Fix{Message: "insert `}`", Span: diag.PosSpan(p.curToken.Pos), Replacement: "}"}
+		p.report(d)
+	}
+
 	return block
 }
 
+// parseFunctionBody analisa o corpo de uma função; break e continue não
+// atravessam a fronteira da função
+func (p *Parser) parseFunctionBody() *ast.BlockStatement {
+	outerLoopDepth := p.loopDepth
+	p.loopDepth = 0
+	defer func() { p.loopDepth = outerLoopDepth }()
+
+	return p.parseBlockStatement()
+}
+
 func (p *Parser) parseFunctionParameters() []*ast.Identifier {
 	identifiers := []*ast.Identifier{}
 
@@ -994,16 +1357,17 @@ func (p *Parser) parseFunctionParameters() []*ast.Identifier {
 		return identifiers
 	}
 
-	p.nextToken()
-
-	ident := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
-	identifiers = append(identifiers, ident)
+	for {
+		ident := p.parseFunctionParameter()
+		if ident == nil {
+			return nil
+		}
+		identifiers = append(identifiers, ident)
 
-	for p.peekTokenIs(lexer.TokenComma) {
-		p.nextToken()
+		if !p.peekTokenIs(lexer.TokenComma) {
+			break
+		}
 		p.nextToken()
-		ident := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
-		identifiers = append(identifiers, ident)
 	}
 
 	if !p.expectPeek(lexer.TokenRParen) {
@@ -1013,6 +1377,29 @@ func (p *Parser) parseFunctionParameters() []*ast.Identifier {
 	return identifiers
 }
 
+// parseFunctionParameter analisa um parâmetro no formato `nome`, `nome: tipo`
+// ou `nome tipo`. O tipo é descartado
+func (p *Parser) parseFunctionParameter() *ast.Identifier {
+	if !p.expectPeek(lexer.TokenIdent) {
+		return nil
+	}
+
+	ident := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if p.peekTokenIs(lexer.TokenColon) {
+		p.nextToken()
+		if p.parseTypeAnnotation() == nil {
+			return nil
+		}
+	} else if p.peekIsTypeStart() {
+		if p.parseTypeAnnotation() == nil {
+			return nil
+		}
+	}
+
+	return ident
+}
+
 func (p *Parser) parseExpressionStatement() *ast.ExpressionStatement {
 	stmt := &ast.ExpressionStatement{Token: p.curToken}
 
@@ -1028,6 +1415,16 @@ func (p *Parser) parseExpressionStatement() *ast.ExpressionStatement {
 func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 	stmt := &ast.ReturnStatement{Token: p.curToken}
 
+	// return sem valor, no fim da linha ou antes de ; ou }, retorna null
+	switch {
+	case p.peekTokenIs(lexer.TokenSemicolon), p.peekTokenIs(lexer.TokenRBrace), p.peekTokenIs(lexer.TokenEOF),
+		p.peekToken.Pos.Line > p.curToken.Pos.Line:
+		if p.peekTokenIs(lexer.TokenSemicolon) {
+			p.nextToken()
+		}
+		return stmt
+	}
+
 	p.nextToken()
 
 	stmt.ReturnValue = p.parseExpression(LOWEST)
@@ -1048,11 +1445,18 @@ func (p *Parser) parseFunctionLiteral() ast.Expression {
 
 	lit.Parameters = p.parseFunctionParameters()
 
+	if p.peekTokenIs(lexer.TokenColon) {
+		p.nextToken()
+		if p.parseTypeAnnotation() == nil {
+			return nil
+		}
+	}
+
 	if !p.expectPeek(lexer.TokenLBrace) {
 		return nil
 	}
 
-	lit.Body = p.parseBlockStatement()
+	lit.Body = p.parseFunctionBody()
 
 	return lit
 }
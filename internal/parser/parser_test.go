@@ -0,0 +1,384 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"jotlango/internal/ast"
+	"jotlango/internal/diag"
+	"jotlango/internal/lexer"
+)
+
+// parse analisa input e falha o teste se houver erros
+func parse(t *testing.T, input string) *ast.Program {
+	t.Helper()
+	p := NewParser(lexer.NewLexer(input))
+	program := p.ParseProgram()
+	for _, d := range p.Diagnostics() {
+		t.Errorf("%q: unexpected diagnostic: %s", input, d)
+	}
+	return program
+}
+
+// parseErrors analisa input e retorna apenas os diagnósticos de erro
+func parseErrors(input string, maxErrors int) []diag.Diagnostic {
+	p := NewParser(lexer.NewLexer(input))
+	if maxErrors > 0 {
+		p.SetMaxErrors(maxErrors)
+	}
+	p.ParseProgram()
+
+	var errors []diag.Diagnostic
+	for _, d := range p.Diagnostics() {
+		if d.Severity == diag.SeverityError {
+			errors = append(errors, d)
+		}
+	}
+	return errors
+}
+
+// singleExpression retorna a expressão da única declaração de program
+func singleExpression(t *testing.T, program *ast.Program) ast.Expression {
+	t.Helper()
+	if len(program.Statements) != 1 {
+		t.Fatalf("expected 1 statement, got %d: %s", len(program.Statements), program)
+	}
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("expected *ast.ExpressionStatement, got %T", program.Statements[0])
+	}
+	return stmt.Expression
+}
+
+func TestIfExpression(t *testing.T) {
+	tests := []struct {
+		input           string
+		condition       string
+		hasAlternative  bool
+		nestedCondition string // condição do else if, se houver
+	}{
+		{"if x < y { x }", "(x < y)", false, ""},
+		{"if x { x } else { y }", "x", true, ""},
+		{"if a { 1 } else if b { 2 }", "a", true, "b"},
+		{"if a { 1 } else if b == c { 2 } else { 3 }", "a", true, "(b == c)"},
+	}
+
+	for _, tt := range tests {
+		program := parse(t, tt.input)
+		exp, ok := singleExpression(t, program).(*ast.IfExpression)
+		if !ok {
+			t.Fatalf("%q: expected *ast.IfExpression", tt.input)
+		}
+		if got := exp.Condition.String(); got != tt.condition {
+			t.Errorf("%q: condition = %s, want %s", tt.input, got, tt.condition)
+		}
+		if len(exp.Consequence.Statements) != 1 {
+			t.Errorf("%q: consequence has %d statements, want 1", tt.input, len(exp.Consequence.Statements))
+		}
+		if (exp.Alternative != nil) != tt.hasAlternative {
+			t.Fatalf("%q: alternative = %v, want present=%v", tt.input, exp.Alternative, tt.hasAlternative)
+		}
+		if tt.nestedCondition == "" {
+			continue
+		}
+
+		// else if é um bloco com uma única IfExpression
+		if len(exp.Alternative.Statements) != 1 {
+			t.Fatalf("%q: else if block has %d statements, want 1", tt.input, len(exp.Alternative.Statements))
+		}
+		nested, ok := exp.Alternative.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.IfExpression)
+		if !ok {
+			t.Fatalf("%q: else if is not an *ast.IfExpression", tt.input)
+		}
+		if got := nested.Condition.String(); got != tt.nestedCondition {
+			t.Errorf("%q: else if condition = %s, want %s", tt.input, got, tt.nestedCondition)
+		}
+	}
+}
+
+func TestIfExpressionErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		code  string
+	}{
+		{"if x 1", diag.CodeUnexpectedToken},
+		{"if x { 1 } else 2", diag.CodeUnexpectedToken},
+		{"if x { 1 } else", diag.CodeUnexpectedToken},
+		{"if { 1 }", diag.CodeUnexpectedToken},
+		{"if ) { 1 }", diag.CodeNoPrefixParse},
+	}
+
+	for _, tt := range tests {
+		errors := parseErrors(tt.input, 0)
+		if len(errors) != 1 {
+			t.Errorf("%q: got %d errors, want 1: %v", tt.input, len(errors), errors)
+			continue
+		}
+		if errors[0].Code != tt.code {
+			t.Errorf("%q: code = %s, want %s", tt.input, errors[0].Code, tt.code)
+		}
+	}
+}
+
+func TestRecoveryReportsOneErrorPerTypo(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		code  string
+		line  int
+	}{
+		{"class method params", "class Pessoa {\n  fn Falar( { print(1) }\n  fn Andar() { print(2) }\n}", diag.CodeUnexpectedToken, 2},
+		{"class without name", "class { }\nvar x = 1", diag.CodeUnexpectedToken, 1},
+		{"unclosed call", "var x = soma(1, 2\nvar y = 3", diag.CodeUnexpectedToken, 2},
+		{"empty call argument", "print(f(1,, 2))\nvar y = 3", diag.CodeNoPrefixParse, 1},
+		{"hash missing colon", "var h = {\"a\": 1, \"b\" 2,\n  \"c\": 3}\nvar y = 3", diag.CodeUnexpectedToken, 1},
+		{"hash missing comma", "var h = {\"a\": 1 \"b\": 2}\nvar y = 3", diag.CodeUnexpectedToken, 1},
+		// O `}` em que o erro ocorre ainda fecha o bloco
+		{"error at closing brace", "fn f() { x = }\nvar y = 1", diag.CodeNoPrefixParse, 1},
+		{"error at method closing brace", "class A {\n  fn B() { 1 * }\n  fn C() { 2 }\n}", diag.CodeNoPrefixParse, 2},
+		{"error at if closing brace", "if x { 1 + }\nvar z = 1", diag.CodeNoPrefixParse, 1},
+	}
+
+	for _, tt := range tests {
+		errors := parseErrors(tt.input, 0)
+		if len(errors) != 1 {
+			t.Errorf("%s: got %d errors, want 1: %v", tt.name, len(errors), errors)
+			continue
+		}
+		if errors[0].Code != tt.code {
+			t.Errorf("%s: code = %s, want %s", tt.name, errors[0].Code, tt.code)
+		}
+		if errors[0].Span.Start.Line != tt.line {
+			t.Errorf("%s: line = %d, want %d", tt.name, errors[0].Span.Start.Line, tt.line)
+		}
+	}
+}
+
+func TestRecoveryContinuesAfterError(t *testing.T) {
+	input := "var x = soma(1, 2\nvar y = {\"a\" 1}\nclass { }\nvar z = 3"
+
+	errors := parseErrors(input, 0)
+	if len(errors) != 3 {
+		t.Fatalf("got %d errors, want 3: %v", len(errors), errors)
+	}
+	for i, line := range []int{2, 2, 3} {
+		if errors[i].Span.Start.Line != line {
+			t.Errorf("error %d: line = %d, want %d", i, errors[i].Span.Start.Line, line)
+		}
+	}
+
+	// A última declaração, depois dos erros, é analisada normalmente
+	p := NewParser(lexer.NewLexer(input))
+	program := p.ParseProgram()
+	last, ok := program.Statements[len(program.Statements)-1].(*ast.VarStatement)
+	if !ok || last.Name.Value != "z" {
+		t.Errorf("last statement = %v, want var z", program.Statements[len(program.Statements)-1])
+	}
+}
+
+// Uma declaração com erro é descartada mesmo que um bloco aninhado nela
+// tenha sido analisado depois do erro
+func TestRecoveryDropsStatementWithNestedBlock(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"var y = 1 + ] (fn() { a })\nvar z = 2", "var z = 2;"},
+		{"g(1 + ] (fn() { a }))\nvar z = 2", "var z = 2;"},
+		{"var z = 2\ng(1 + ] (fn() { if a { b } }))", "var z = 2;"},
+	}
+
+	for _, tt := range tests {
+		p := NewParser(lexer.NewLexer(tt.input))
+		program := p.ParseProgram()
+		if errors := p.Diagnostics(); len(errors) != 1 {
+			t.Errorf("%q: got %d errors, want 1: %v", tt.input, len(errors), errors)
+		}
+		if got := program.String(); got != tt.want {
+			t.Errorf("%q: got %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestMaxErrors(t *testing.T) {
+	input := ""
+	for i := 0; i < 10; i++ {
+		input += "var = 1\n"
+	}
+
+	if errors := parseErrors(input, 0); len(errors) != 10 {
+		t.Errorf("without a cap: got %d errors, want 10", len(errors))
+	}
+
+	errors := parseErrors(input, 3)
+	if len(errors) != 3 {
+		t.Fatalf("with a cap of 3: got %d errors, want 3", len(errors))
+	}
+	for _, d := range errors {
+		if d.Code != diag.CodeUnexpectedToken {
+			t.Errorf("code = %s, want %s", d.Code, diag.CodeUnexpectedToken)
+		}
+	}
+	if related := errors[2].Related; len(related) != 1 || related[0].Message != "too many errors (3), parsing stopped" {
+		t.Errorf("last error notes = %v, want the too many errors note", related)
+	}
+
+	if errors := parseErrors(input+input+input, 0); len(errors) != DefaultMaxErrors {
+		t.Errorf("default cap: got %d errors, want %d", len(errors), DefaultMaxErrors)
+	}
+}
+
+func TestOperatorPrecedence(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"2 ** 3 ** 2", "(2 ** (3 ** 2))"},
+		{"-2 ** 2", "(-(2 ** 2))"},
+		{"2 ** -1", "(2 ** (-1))"},
+		{"a * b ** c", "(a * (b ** c))"},
+		{"a & 1 == 0", "((a & 1) == 0)"},
+		{"a | b ^ c & d", "(a | (b ^ (c & d)))"},
+		{"a << 1 + b", "(a << (1 + b))"},
+		{"a & b < c", "((a & b) < c)"},
+		{"~a & b", "((~a) & b)"},
+		{"a <= b == c >= d", "((a <= b) == (c >= d))"},
+		{"a || b && c", "(a || (b && c))"},
+		{"a && b || c && d", "((a && b) || (c && d))"},
+		{"!a || b", "((!a) || b)"},
+		{"a == b && c != d", "((a == b) && (c != d))"},
+		{"a < b || c", "((a < b) || c)"},
+		{"a % b * c", "((a % b) * c)"},
+		{"-x++", "(-(x++))"},
+		{"a[0]++ + 1", "(((a[0])++) + 1)"},
+		{"x += y * 2", "x += (y * 2)"},
+		{"x = y += 1", "x = y += 1"},
+		{"a.b -= c || d", "a.b -= (c || d)"},
+	}
+
+	for _, tt := range tests {
+		program := parse(t, tt.input)
+		if got := program.String(); got != tt.want {
+			t.Errorf("%q: got %s, want %s", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestCompoundAssignmentAndPostfix(t *testing.T) {
+	tests := []struct {
+		input    string
+		operator string
+		target   string
+	}{
+		{"x += 1", "+", "x"},
+		{"x -= 1", "-", "x"},
+		{"x *= 2", "*", "x"},
+		{"x /= 2", "/", "x"},
+		{"x %= 2", "%", "x"},
+		{"x = 1", "", "x"},
+		{"a[i] += 1", "+", "(a[i])"},
+		{"this.Total -= 1", "-", "this.Total"},
+	}
+
+	for _, tt := range tests {
+		exp, ok := singleExpression(t, parse(t, tt.input)).(*ast.AssignmentExpression)
+		if !ok {
+			t.Errorf("%q: expected *ast.AssignmentExpression", tt.input)
+			continue
+		}
+		if exp.Operator != tt.operator || exp.Left.String() != tt.target {
+			t.Errorf("%q: operator %q on %s, want %q on %s", tt.input, exp.Operator, exp.Left, tt.operator, tt.target)
+		}
+	}
+
+	for _, input := range []string{"x++", "x--", "a[i]++", "this.Count--"} {
+		if _, ok := singleExpression(t, parse(t, input)).(*ast.PostfixExpression); !ok {
+			t.Errorf("%q: expected *ast.PostfixExpression", input)
+		}
+	}
+
+	for _, input := range []string{"1 += 2", "f() -= 1", "1++", "(a + b)--", "(a.)++", "(a.) = 1", "(a.) += 1"} {
+		if errors := parseErrors(input, 0); len(errors) != 1 {
+			t.Errorf("%q: got %d errors, want 1: %v", input, len(errors), errors)
+		}
+	}
+}
+
+func TestReturnStatement(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"fn f() { return 1 }", "return 1;"},
+		{"fn f() { return }", "return;"},
+		{"fn f() { return; }", "return;"},
+		{"fn f() {\n  return\n}", "return;"},
+		{"fn f() {\n  if x { return }\n  y\n}", "return;"},
+		{"fn f() {\n  return a +\n    b\n}", "return (a + b);"},
+	}
+
+	for _, tt := range tests {
+		program := parse(t, tt.input)
+		if got := program.String(); !strings.Contains(got, tt.want) {
+			t.Errorf("%q: got %s, want %s", tt.input, got, tt.want)
+		}
+	}
+}
+
+// Os comentários /// vão para a declaração seguinte: classes, interfaces,
+// funções, métodos, propriedades e assinaturas
+func TestDocComments(t *testing.T) {
+	program := parse(t, `/// Uma conta
+/// bancária
+class Conta {
+    /// Saldo atual
+    prop saldo: float = 0
+
+    // comentário comum
+    /// Deposita valor
+    fn depositar(valor) { this.saldo += valor }
+
+    fn sacar(valor) { this.saldo -= valor }
+}
+
+/// Algo que tem nome
+interface Nomeado {
+    /// Retorna o nome
+    fn nome(): string
+}
+
+/// Soma a e b
+fn soma(a, b) { a + b }
+
+fn semDoc() { 1 }`)
+
+	if len(program.Statements) != 4 {
+		t.Fatalf("got %d statements, want 4", len(program.Statements))
+	}
+
+	class := program.Statements[0].(*ast.ClassStatement)
+	prop := class.Body.Statements[0].(*ast.PropertyStatement)
+	deposit := class.Body.Statements[1].(*ast.FunctionStatement)
+	withdraw := class.Body.Statements[2].(*ast.FunctionStatement)
+	iface := program.Statements[1].(*ast.InterfaceStatement)
+	sum := program.Statements[2].(*ast.FunctionStatement)
+	undocumented := program.Statements[3].(*ast.FunctionStatement)
+
+	tests := []struct {
+		name, got, want string
+	}{
+		{"class", class.Doc, "Uma conta\nbancária"},
+		{"prop", prop.Doc, "Saldo atual"},
+		{"method", deposit.Doc, "Deposita valor"},
+		{"undocumented method", withdraw.Doc, ""},
+		{"interface", iface.Doc, "Algo que tem nome"},
+		{"signature", iface.Methods[0].Doc, "Retorna o nome"},
+		{"function", sum.Doc, "Soma a e b"},
+		{"undocumented function", undocumented.Doc, ""},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: Doc = %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package stdlib
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"jotlango/internal/object"
+)
+
+// runWeb avalia src em um projeto cujo jot.json aponta "web" para o
+// framework do repositório
+func runWeb(t *testing.T, src string) object.Object {
+	t.Helper()
+	framework, err := filepath.Abs("../../framework/web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	project, err := json.Marshal(map[string]interface{}{
+		"name":         "test",
+		"dependencies": map[string]string{"web": framework},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "jot.json"), project, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return runFile(t, filepath.Join(dir, "main.jt"), src)
+}
+
+// middlewares registra um log compartilhado e middlewares que anotam nele
+// a entrada e a saída de cada etapa
+const middlewares = `import "web"
+
+var log = []
+
+fn marca(nome) {
+    return fn(req, next) {
+        log = push(log, nome)
+        var res = next()
+        log = push(log, "/" + nome)
+        return res
+    }
+}
+
+class Auth : web.Middleware {
+    fn Handle(req: Request, next): Response {
+        if req.GetHeader("Authorization") == "" {
+            log = push(log, "negado")
+            return new web.Response().Status(401).Text("unauthorized")
+        }
+        req.SetContext("user", req.GetHeader("Authorization"))
+        return next()
+    }
+}
+
+var auth = new Auth()
+var a = marca("a")
+var b = marca("b")
+
+var app = new web.Server()
+app.Use(a)
+app.Get("/open", fn(req) {
+    log = push(log, "handler")
+    return new web.Response().Text("open")
+}).Use(marca("rota"))
+app.Use(b)
+app.Get("/private", fn(req) {
+    log = push(log, "handler")
+    return new web.Response().Text("hello " + req.GetContext("user"))
+}).Use(auth)
+app.Get("/skip", fn(req) {
+    log = push(log, "handler")
+    return new web.Response().Text("skip")
+}).Skip(a)
+
+fn pedir(path, headers) {
+    log = []
+    var res = app.Test("GET", path, "", headers)
+    return "${res.Status} ${res.Body} ${join(log, ",")}"
+}
+`
+
+func TestMiddlewareChain(t *testing.T) {
+	tests := []struct {
+		call string
+		want string
+	}{
+		// Globais na ordem de registro, inclusive b, registrado depois da
+		// rota; depois os da rota e o handler. A saída volta na ordem inversa
+		{`pedir("/open", null)`, "200 open a,b,rota,handler,/rota,/b,/a"},
+		// Um middleware que não chama next interrompe a cadeia
+		{`pedir("/private", null)`, "401 unauthorized a,b,negado,/b,/a"},
+		// Valores do contexto chegam ao handler
+		{`pedir("/private", {"Authorization": "ana"})`, "200 hello ana a,b,handler,/b,/a"},
+		// Skip desativa um middleware global só nesta rota
+		{`pedir("/skip", null)`, "200 skip b,handler,/b"},
+	}
+
+	for _, tt := range tests {
+		result := runWeb(t, middlewares+tt.call)
+		if errObj, ok := result.(*object.Error); ok {
+			t.Errorf("%s: %s", tt.call, errObj.Inspect())
+			continue
+		}
+		if got := result.Inspect(); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.call, got, tt.want)
+		}
+	}
+}
+
+// Uma classe que não implementa web.Middleware é recusada na definição
+func TestMiddlewareContract(t *testing.T) {
+	result := runWeb(t, `import "web"
+
+class Quebrado : web.Middleware {
+    fn Handle(req) { null }
+}`)
+	errObj, ok := result.(*object.Error)
+	if !ok {
+		t.Fatalf("got %s, want an error", result.Inspect())
+	}
+	if want := "class Quebrado does not implement Middleware: method Handle takes 1 parameters, want 2"; errObj.Message != want {
+		t.Errorf("message = %q, want %q", errObj.Message, want)
+	}
+}
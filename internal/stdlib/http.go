@@ -0,0 +1,379 @@
+package stdlib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"jotlango/internal/eval"
+	"jotlango/internal/object"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// HTTP_SERVER_OBJ é o tipo do handle opaco que http.jt guarda em Server
+const HTTP_SERVER_OBJ = "HTTP_SERVER"
+
+// shutdownTimeout é quanto Listen espera as requisições em andamento
+// terminarem antes de fechar as conexões
+const shutdownTimeout = 5 * time.Second
+
+// Funções nativas do servidor HTTP. Os handlers JotLang recebem a requisição
+// como um hash (method, path, params, query, headers, body) e retornam um
+// hash (status, headers, body), um objeto com o método toHash(), uma string
+// ou null; as classes de http.jt fazem a conversão de e para Request e
+// Response
+func init() {
+	eval.RegisterNatives("http", map[string]interface{}{
+		"NewServer":       newHTTPServer,
+		"Route":           httpRoute,
+		"Listen":          httpListen,
+		"Shutdown":        httpShutdown,
+		"Test":            httpTest,
+		"SetHeader":       httpSetHeader,
+		"CanonicalHeader": http.CanonicalHeaderKey,
+	})
+}
+
+// HTTPServer roteia requisições para handlers JotLang. Implementa
+// http.Handler, então pode ser testado com httptest sem abrir portas. Cada
+// requisição roda seu handler na própria goroutine, ao mesmo tempo que as
+// outras
+type HTTPServer struct {
+	routes []*route
+
+	serverMu sync.Mutex
+	server   *http.Server
+}
+
+func (s *HTTPServer) Type() object.ObjectType { return HTTP_SERVER_OBJ }
+func (s *HTTPServer) Inspect() string         { return fmt.Sprintf("<http server: %d routes>", len(s.routes)) }
+
+type route struct {
+	method   string
+	segments []string
+	handler  object.Object
+}
+
+func newHTTPServer() *HTTPServer {
+	return &HTTPServer{}
+}
+
+// httpRoute registra handler para method e pattern. Segmentos no formato
+// {nome} capturam a parte correspondente do caminho
+func httpRoute(s *HTTPServer, method string, pattern string, handler object.Object) error {
+	segments := splitPath(pattern)
+	for _, segment := range segments {
+		if strings.HasPrefix(segment, "{") != strings.HasSuffix(segment, "}") || segment == "{}" {
+			return fmt.Errorf("invalid route pattern %q: malformed parameter %q", pattern, segment)
+		}
+	}
+
+	s.routes = append(s.routes, &route{
+		method:   strings.ToUpper(method),
+		segments: segments,
+		handler:  handler,
+	})
+	return nil
+}
+
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+// match retorna os parâmetros de caminho se path corresponder à rota
+func (r *route) match(segments []string) (map[string]string, bool) {
+	if len(segments) != len(r.segments) {
+		return nil, false
+	}
+
+	params := map[string]string{}
+	for i, segment := range r.segments {
+		if strings.HasPrefix(segment, "{") {
+			params[segment[1:len(segment)-1]] = segments[i]
+		} else if segment != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+func (s *HTTPServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	segments := splitPath(r.URL.Path)
+
+	var allowed []string
+	for _, route := range s.routes {
+		params, ok := route.match(segments)
+		if !ok {
+			continue
+		}
+		if route.method != r.Method {
+			allowed = append(allowed, route.method)
+			continue
+		}
+		s.handle(w, r, route, params)
+		return
+	}
+
+	if len(allowed) > 0 {
+		w.Header().Set("Allow", strings.Join(allowed, ", "))
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	http.NotFound(w, r)
+}
+
+func (s *HTTPServer) handle(w http.ResponseWriter, r *http.Request, route *route, params map[string]string) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	query := map[string]string{}
+	for key, values := range r.URL.Query() {
+		query[key] = values[0]
+	}
+
+	headers := map[string]string{}
+	for key := range r.Header {
+		headers[key] = r.Header.Get(key)
+	}
+
+	request := eval.FromGo(map[string]interface{}{
+		"method":  r.Method,
+		"path":    r.URL.Path,
+		"params":  params,
+		"query":   query,
+		"headers": headers,
+		"body":    string(body),
+	})
+
+	res, err := buildResponse(eval.Call(route.handler, request))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %s: %s\n", r.Method, r.URL.Path, err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	res.write(w)
+}
+
+// response é o retorno de um handler já convertido para Go, pronto para
+// ser escrito sem acessar objetos do avaliador
+type response struct {
+	status  int
+	headers http.Header
+	body    []byte
+}
+
+// buildResponse converte o retorno de um handler. Corpos que não são
+// strings são serializados como JSON
+func buildResponse(result object.Object) (*response, error) {
+	res := &response{status: http.StatusOK, headers: http.Header{}}
+	var body object.Object = eval.NULL
+
+	if instance, ok := result.(*eval.Instance); ok {
+		method, owner, ok := instance.Class.FindMethod("toHash")
+		if !ok {
+			return nil, fmt.Errorf("handler returned %s, which has no toHash method", instance.Class.Name)
+		}
+		result = eval.Call(&eval.BoundMethod{Receiver: instance, Owner: owner, Method: method, Name: "toHash"})
+	}
+
+	switch result := result.(type) {
+	case *object.Null:
+		res.status = http.StatusNoContent
+	case *object.Error:
+		return nil, errors.New(result.Inspect())
+	case *object.String:
+		body = result
+	case *object.Hash:
+		fields := hashFields(result)
+
+		if value, ok := fields["status"]; ok {
+			n, ok := value.(*object.Integer)
+			if !ok || n.Value < 100 || n.Value > 999 {
+				return nil, fmt.Errorf("invalid response status: %s", value.Inspect())
+			}
+			res.status = int(n.Value)
+		}
+
+		if value, ok := fields["headers"]; ok && value != eval.NULL {
+			headers, ok := value.(*object.Hash)
+			if !ok {
+				return nil, fmt.Errorf("response headers must be HASH, got %s", value.Type())
+			}
+			for name, value := range hashFields(headers) {
+				res.headers.Set(name, stringValue(value))
+			}
+		}
+
+		if value, ok := fields["body"]; ok {
+			body = value
+		}
+	default:
+		return nil, fmt.Errorf("handler must return a Response, got %s", result.Type())
+	}
+
+	switch body := body.(type) {
+	case *object.String:
+		res.body = []byte(body.Value)
+		if res.headers.Get("Content-Type") == "" {
+			res.headers.Set("Content-Type", "text/plain; charset=utf-8")
+		}
+	case *object.Null:
+	default:
+		encoded, err := encodeJSON(body, "")
+		if err != nil {
+			return nil, fmt.Errorf("cannot encode response body: %v", err)
+		}
+		res.body = encoded
+		if res.headers.Get("Content-Type") == "" {
+			res.headers.Set("Content-Type", "application/json")
+		}
+	}
+
+	return res, nil
+}
+
+func (res *response) write(w http.ResponseWriter) {
+	for name, values := range res.headers {
+		w.Header()[name] = values
+	}
+	w.WriteHeader(res.status)
+	if len(res.body) > 0 {
+		w.Write(res.body)
+	}
+}
+
+// hashFields indexa um hash pelas chaves em forma de string
+func hashFields(hash *object.Hash) map[string]object.Object {
+	pairs := hash.Ordered()
+	fields := make(map[string]object.Object, len(pairs))
+	for _, pair := range pairs {
+		fields[stringValue(pair.Key)] = pair.Value
+	}
+	return fields
+}
+
+func stringValue(obj object.Object) string {
+	if s, ok := obj.(*object.String); ok {
+		return s.Value
+	}
+	return obj.Inspect()
+}
+
+// httpListen atende em port até receber SIGINT ou SIGTERM, ou até
+// Shutdown ser chamado, e então encerra esperando as requisições em
+// andamento
+func httpListen(s *HTTPServer, port int) error {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return err
+	}
+
+	server := &http.Server{Handler: s}
+	s.serverMu.Lock()
+	s.server = server
+	s.serverMu.Unlock()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	go func() {
+		if _, ok := <-signals; ok {
+			shutdownServer(server)
+		}
+	}()
+
+	if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// httpShutdown pede o encerramento do servidor sem bloquear, para que
+// possa ser chamado de dentro de um handler
+func httpShutdown(s *HTTPServer) {
+	s.serverMu.Lock()
+	server := s.server
+	s.serverMu.Unlock()
+
+	if server != nil {
+		go shutdownServer(server)
+	}
+}
+
+func shutdownServer(server *http.Server) {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		server.Close()
+	}
+}
+
+// httpTest envia uma requisição ao servidor sem usar a rede e retorna a
+// resposta como um hash (status, headers, body)
+func httpTest(s *HTTPServer, method string, target string, body string, headers map[string]string) (object.Object, error) {
+	request, err := http.NewRequest(strings.ToUpper(method), target, strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range headers {
+		request.Header.Set(name, value)
+	}
+
+	recorder := &responseRecorder{status: http.StatusOK, header: http.Header{}}
+	s.ServeHTTP(recorder, request)
+
+	responseHeaders := map[string]string{}
+	for key := range recorder.header {
+		responseHeaders[key] = recorder.header.Get(key)
+	}
+
+	return eval.FromGo(map[string]interface{}{
+		"status":  recorder.status,
+		"headers": responseHeaders,
+		"body":    recorder.body.String(),
+	}), nil
+}
+
+// responseRecorder guarda a resposta de ServeHTTP em memória para httpTest
+type responseRecorder struct {
+	status      int
+	header      http.Header
+	body        strings.Builder
+	wroteHeader bool
+}
+
+func (r *responseRecorder) Header() http.Header { return r.header }
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+}
+
+func (r *responseRecorder) Write(p []byte) (int, error) {
+	r.WriteHeader(http.StatusOK)
+	return r.body.Write(p)
+}
+
+// httpSetHeader define um header em um hash, normalizando o nome
+func httpSetHeader(headers *object.Hash, name string, value string) *object.Hash {
+	key := &object.String{Value: http.CanonicalHeaderKey(name)}
+	headers.Set(key.HashKey(), object.HashPair{Key: key, Value: &object.String{Value: value}})
+	return headers
+}
@@ -0,0 +1,84 @@
+package stdlib
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"jotlango/internal/eval"
+	"jotlango/internal/object"
+	"net/http"
+	"time"
+)
+
+// Funções nativas do cliente HTTP
+func init() {
+	eval.RegisterNatives("http", map[string]interface{}{
+		"Request":    httpDo,
+		"DecodeJSON": decodeJSON,
+	})
+}
+
+// httpDo faz uma requisição e retorna a resposta como um hash (status,
+// headers, body). Strings são enviadas como estão; hashes, arrays e demais
+// valores são codificados como JSON; null não envia corpo. Os headers da
+// requisição prevalecem sobre os padrão. timeout é em segundos; 0 desativa
+func httpDo(method string, url string, body object.Object, defaults map[string]string, headers map[string]string, timeout float64) (object.Object, error) {
+	var reader io.Reader
+	contentType := ""
+
+	switch body := body.(type) {
+	case *object.Null:
+	case *object.String:
+		reader = bytes.NewBufferString(body.Value)
+		contentType = "text/plain; charset=utf-8"
+	default:
+		encoded, err := encodeJSON(body, "")
+		if err != nil {
+			return nil, fmt.Errorf("cannot encode request body: %v", err)
+		}
+		reader = bytes.NewReader(encoded)
+		contentType = "application/json"
+	}
+
+	request, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		return nil, err
+	}
+
+	if contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+	for name, value := range defaults {
+		request.Header.Set(name, value)
+	}
+	for name, value := range headers {
+		request.Header.Set(name, value)
+	}
+
+	if timeout < 0 {
+		return nil, fmt.Errorf("invalid timeout: %g", timeout)
+	}
+	client := &http.Client{Timeout: time.Duration(timeout * float64(time.Second))}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	content, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: reading body: %v", method, url, err)
+	}
+
+	responseHeaders := map[string]string{}
+	for key := range response.Header {
+		responseHeaders[key] = response.Header.Get(key)
+	}
+
+	return eval.FromGo(map[string]interface{}{
+		"status":  response.StatusCode,
+		"headers": responseHeaders,
+		"body":    string(content),
+	}), nil
+}
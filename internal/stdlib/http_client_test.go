@@ -0,0 +1,189 @@
+package stdlib
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"jotlango/internal/eval"
+	"jotlango/internal/object"
+)
+
+// echoServer responde com um JSON descrevendo a requisição recebida.
+// /status/404 responde 404 e /slow demora mais que os timeouts dos testes
+func echoServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/status/404":
+			w.Header().Set("X-Reason", "missing")
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, "not here")
+			return
+		case "/slow":
+			time.Sleep(200 * time.Millisecond)
+		}
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{
+			"method":      r.Method,
+			"path":        r.URL.Path,
+			"contentType": r.Header.Get("Content-Type"),
+			"token":       r.Header.Get("X-Token"),
+			"body":        string(body),
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHTTPClientRequests(t *testing.T) {
+	server := echoServer(t)
+	tests := []struct {
+		name     string
+		method   string
+		body     object.Object
+		defaults map[string]string
+		headers  map[string]string
+		want     map[string]string
+	}{
+		{
+			name:   "GET without body",
+			method: "GET",
+			body:   eval.NULL,
+			want:   map[string]string{"method": "GET", "contentType": "", "body": ""},
+		},
+		{
+			name:   "POST string",
+			method: "POST",
+			body:   &object.String{Value: "hello"},
+			want:   map[string]string{"method": "POST", "contentType": "text/plain; charset=utf-8", "body": "hello"},
+		},
+		{
+			name:   "PUT hash as JSON",
+			method: "PUT",
+			body:   eval.FromGo(map[string]interface{}{"name": "Ana"}),
+			want:   map[string]string{"method": "PUT", "contentType": "application/json", "body": `{"name":"Ana"}`},
+		},
+		{
+			name:   "DELETE",
+			method: "DELETE",
+			body:   eval.NULL,
+			want:   map[string]string{"method": "DELETE", "body": ""},
+		},
+		{
+			name:     "default headers",
+			method:   "GET",
+			body:     eval.NULL,
+			defaults: map[string]string{"X-Token": "default"},
+			want:     map[string]string{"token": "default"},
+		},
+		{
+			name:     "request headers win over defaults",
+			method:   "POST",
+			body:     &object.String{Value: "{}"},
+			defaults: map[string]string{"X-Token": "default"},
+			headers:  map[string]string{"X-Token": "mine", "Content-Type": "application/json"},
+			want:     map[string]string{"token": "mine", "contentType": "application/json"},
+		},
+	}
+
+	for _, tt := range tests {
+		result, err := httpDo(tt.method, server.URL+"/echo", tt.body, tt.defaults, tt.headers, 5)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		fields := hashFields(result.(*object.Hash))
+		if status := fields["status"].Inspect(); status != "200" {
+			t.Errorf("%s: status = %s, want 200", tt.name, status)
+		}
+		if ct := hashFields(fields["headers"].(*object.Hash))["Content-Type"]; ct.Inspect() != "application/json" {
+			t.Errorf("%s: response Content-Type = %s", tt.name, ct.Inspect())
+		}
+
+		var echoed map[string]string
+		if err := json.Unmarshal([]byte(fields["body"].Inspect()), &echoed); err != nil {
+			t.Errorf("%s: body %s: %v", tt.name, fields["body"].Inspect(), err)
+			continue
+		}
+		for key, want := range tt.want {
+			if echoed[key] != want {
+				t.Errorf("%s: %s = %q, want %q", tt.name, key, echoed[key], want)
+			}
+		}
+	}
+}
+
+// Status fora de 2xx não é um erro: a resposta é retornada normalmente
+func TestHTTPClientErrorStatus(t *testing.T) {
+	server := echoServer(t)
+	result, err := httpDo("GET", server.URL+"/status/404", eval.NULL, nil, nil, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := hashFields(result.(*object.Hash))
+	if status := fields["status"].Inspect(); status != "404" {
+		t.Errorf("status = %s, want 404", status)
+	}
+	if body := fields["body"].Inspect(); body != "not here" {
+		t.Errorf("body = %q, want %q", body, "not here")
+	}
+	if reason := hashFields(fields["headers"].(*object.Hash))["X-Reason"]; reason == nil || reason.Inspect() != "missing" {
+		t.Errorf("X-Reason = %v, want missing", reason)
+	}
+}
+
+func TestHTTPClientFailures(t *testing.T) {
+	server := echoServer(t)
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	tests := []struct {
+		name    string
+		url     string
+		timeout float64
+		want    string
+	}{
+		{"connection refused", closed.URL + "/", 5, "connect"},
+		{"timeout", server.URL + "/slow", 0.05, "Timeout"},
+		{"negative timeout", server.URL + "/", -1, "invalid timeout"},
+		{"invalid url", "http://[::1", 5, "missing ']'"},
+	}
+
+	for _, tt := range tests {
+		_, err := httpDo("GET", tt.url, eval.NULL, nil, nil, tt.timeout)
+		if err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error = %q, want it to contain %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+// O Client de http.jt junta BaseURL e caminho, envia Client.Headers e
+// decodifica a resposta
+func TestHTTPClientFromJotLang(t *testing.T) {
+	server := echoServer(t)
+	result := run(t, `import "http"
+
+var client = new http.Client()
+client.BaseURL = "`+server.URL+`"
+client.Headers = {"X-Token": "abc"}
+
+var created = client.post("/users", {"name": "Ana"})
+var missing = client.get("/status/404")
+var data = created.json()
+"${created.ok()} ${data["method"]} ${data["path"]} ${data["token"]} ${data["body"]} ${missing.ok()} ${missing.Status} ${missing.header("x-reason")}"`)
+
+	want := `true POST /users abc {"name":"Ana"} false 404 missing`
+	if got := result.Inspect(); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
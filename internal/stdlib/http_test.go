@@ -0,0 +1,283 @@
+package stdlib
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"jotlango/internal/eval"
+	"jotlango/internal/lexer"
+	"jotlango/internal/object"
+	"jotlango/internal/parser"
+)
+
+// handler avalia src, que deve ser uma função JotLang anônima
+func handler(t *testing.T, src string) object.Object {
+	t.Helper()
+	p := parser.NewParser(lexer.NewFileLexer("test.jt", "var handler = "+src+"\nhandler"))
+	program := p.ParseProgram()
+	for _, d := range p.Diagnostics() {
+		t.Fatalf("%q: unexpected diagnostic: %s", src, d)
+	}
+	fn := eval.Eval(program, object.NewEnvironment())
+	if fn.Type() != object.FUNCTION_OBJ {
+		t.Fatalf("%q: got %s, want a function", src, fn.Inspect())
+	}
+	return fn
+}
+
+// testServer monta um servidor com as rotas usadas pelos testes
+func testServer(t *testing.T) *HTTPServer {
+	t.Helper()
+	s := newHTTPServer()
+	routes := []struct {
+		method, pattern, handler string
+	}{
+		{"GET", "/", `fn(req) { "home" }`},
+		{"GET", "/users/{id}", `fn(req) { "user " + req["params"]["id"] }`},
+		{"DELETE", "/users/{id}", `fn(req) { null }`},
+		{"GET", "/users/{id}/posts/{post}", `fn(req) { req["params"]["id"] + ":" + req["params"]["post"] }`},
+		{"POST", "/echo", `fn(req) { {"status": 201, "body": {"received": req["body"], "tags": [1, 2]}} }`},
+		{"GET", "/custom", `fn(req) { {"headers": {"Content-Type": "text/csv"}, "body": "a,b"} }`},
+		{"GET", "/fail", `fn(req) { 1 / 0 }`},
+	}
+	for _, r := range routes {
+		if err := httpRoute(s, r.method, r.pattern, handler(t, r.handler)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return s
+}
+
+func serve(s *HTTPServer, method, target, body string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	s.ServeHTTP(recorder, httptest.NewRequest(method, target, strings.NewReader(body)))
+	return recorder
+}
+
+func TestServerRouting(t *testing.T) {
+	s := testServer(t)
+	tests := []struct {
+		method, target string
+		status         int
+		body           string
+	}{
+		{"GET", "/", http.StatusOK, "home"},
+		{"GET", "/users/42", http.StatusOK, "user 42"},
+		{"GET", "/users/42/", http.StatusOK, "user 42"},
+		{"GET", "/users/7/posts/abc", http.StatusOK, "7:abc"},
+		{"DELETE", "/users/42", http.StatusNoContent, ""},
+		{"GET", "/missing", http.StatusNotFound, "404 page not found\n"},
+		{"GET", "/users", http.StatusNotFound, "404 page not found\n"},
+		{"GET", "/users/1/posts", http.StatusNotFound, "404 page not found\n"},
+		{"GET", "/fail", http.StatusInternalServerError, "Internal Server Error\n"},
+	}
+
+	for _, tt := range tests {
+		recorder := serve(s, tt.method, tt.target, "")
+		if recorder.Code != tt.status {
+			t.Errorf("%s %s: status = %d, want %d", tt.method, tt.target, recorder.Code, tt.status)
+		}
+		if got := recorder.Body.String(); got != tt.body {
+			t.Errorf("%s %s: body = %q, want %q", tt.method, tt.target, got, tt.body)
+		}
+	}
+}
+
+func TestServerMethodNotAllowed(t *testing.T) {
+	recorder := serve(testServer(t), "PUT", "/users/42", "")
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Errorf("status = %d, want %d", recorder.Code, http.StatusMethodNotAllowed)
+	}
+	if allow := recorder.Header().Get("Allow"); allow != "GET, DELETE" {
+		t.Errorf("Allow = %q, want %q", allow, "GET, DELETE")
+	}
+}
+
+func TestServerResponseBodies(t *testing.T) {
+	s := testServer(t)
+
+	recorder := serve(s, "POST", "/echo", "hello")
+	if recorder.Code != http.StatusCreated {
+		t.Errorf("status = %d, want %d", recorder.Code, http.StatusCreated)
+	}
+	if ct := recorder.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+	if got, want := recorder.Body.String(), `{"received":"hello","tags":[1,2]}`; got != want {
+		t.Errorf("body = %s, want %s", got, want)
+	}
+
+	recorder = serve(s, "GET", "/", "")
+	if ct := recorder.Header().Get("Content-Type"); ct != "text/plain; charset=utf-8" {
+		t.Errorf("Content-Type = %q, want text/plain", ct)
+	}
+
+	// Um Content-Type definido pelo handler não é substituído
+	recorder = serve(s, "GET", "/custom", "")
+	if ct := recorder.Header().Get("Content-Type"); ct != "text/csv" {
+		t.Errorf("Content-Type = %q, want text/csv", ct)
+	}
+}
+
+func TestRouteRejectsMalformedParameters(t *testing.T) {
+	for _, pattern := range []string{"/users/{id", "/users/id}", "/users/{}"} {
+		if err := httpRoute(newHTTPServer(), "GET", pattern, eval.NULL); err == nil {
+			t.Errorf("%q: expected an error", pattern)
+		}
+	}
+}
+
+// httpTest é o que o método test() de http.jt usa
+func TestHTTPTest(t *testing.T) {
+	result, err := httpTest(testServer(t), "post", "/echo", "hi", map[string]string{"X-Id": "1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := hashFields(result.(*object.Hash))
+	if status := fields["status"].Inspect(); status != "201" {
+		t.Errorf("status = %s, want 201", status)
+	}
+	if body := fields["body"].Inspect(); body != `{"received":"hi","tags":[1,2]}` {
+		t.Errorf("body = %s", body)
+	}
+	headers := hashFields(fields["headers"].(*object.Hash))
+	if ct := headers["Content-Type"].Inspect(); ct != "application/json" {
+		t.Errorf("Content-Type = %s, want application/json", ct)
+	}
+
+	if _, err := httpTest(testServer(t), "GET", "http://[::1", "", nil); err == nil {
+		t.Error("expected an error for an invalid target")
+	}
+}
+
+// Um handler bloqueado não impede que outro rode: /wait só termina depois
+// que /release é atendido
+func TestServerHandlersRunConcurrently(t *testing.T) {
+	released := make(chan struct{})
+	s := newHTTPServer()
+	wait := &object.Builtin{Fn: func(args ...object.Object) object.Object {
+		select {
+		case <-released:
+			return &object.String{Value: "released"}
+		case <-time.After(5 * time.Second):
+			return &object.Error{Message: "timed out waiting for /release"}
+		}
+	}}
+	release := &object.Builtin{Fn: func(args ...object.Object) object.Object {
+		close(released)
+		return eval.NULL
+	}}
+	if err := httpRoute(s, "GET", "/wait", wait); err != nil {
+		t.Fatal(err)
+	}
+	if err := httpRoute(s, "GET", "/release", release); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- serve(s, "GET", "/wait", "") }()
+	serve(s, "GET", "/release", "")
+
+	if recorder := <-done; recorder.Body.String() != "released" {
+		t.Errorf("/wait: status %d, body %q", recorder.Code, recorder.Body.String())
+	}
+}
+
+// Handlers concorrentes podem ler e alterar variáveis, hashes, arrays e
+// objetos compartilhados; rode com -race para verificar
+func TestServerHandlersShareState(t *testing.T) {
+	result := run(t, `import "http"
+
+class Stats {
+    prop last = ""
+}
+
+var server = new http.Server()
+var hits = {}
+var items = [0]
+var stats = new Stats()
+var total = 0
+
+server.get("/hit/{id}", fn(req) {
+    var id = req.param("id")
+    hits[id] = true
+    items[0] = id
+    items[0:0] = [id]
+    stats.last = id
+    total = total + 1
+    return "${len(hits)} ${len(items)} ${stats} ${total}"
+})
+
+var shared = [server, hits, items]
+shared`)
+	values, ok := result.(*object.Array)
+	if !ok {
+		t.Fatalf("got %s, want an array", result.Inspect())
+	}
+	elements := values.Snapshot()
+	s := httpHandle(t, elements[0])
+
+	const requests = 50
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if recorder := serve(s, "GET", fmt.Sprintf("/hit/%d", i), ""); recorder.Code != http.StatusOK {
+				t.Errorf("/hit/%d: status %d, body %q", i, recorder.Code, recorder.Body.String())
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if n := elements[1].(*object.Hash).Len(); n != requests {
+		t.Errorf("len(hits) = %d, want %d", n, requests)
+	}
+	if n := elements[2].(*object.Array).Len(); n != requests+1 {
+		t.Errorf("len(items) = %d, want %d", n, requests+1)
+	}
+}
+
+// Um handler pode chamar o próprio servidor sem travar
+func TestHandlerCallsOwnServer(t *testing.T) {
+	result := run(t, `import "http"
+
+var server = new http.Server()
+server.get("/inner", fn(req) { "inner" })
+server.get("/outer", fn(req) {
+    return "outer " + server.test("GET", "/inner", "", null).Body
+})
+server`)
+	s := httpHandle(t, result)
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- serve(s, "GET", "/outer", "") }()
+	select {
+	case recorder := <-done:
+		if got := recorder.Body.String(); got != "outer inner" {
+			t.Errorf("body = %q, want %q (status %d)", got, "outer inner", recorder.Code)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("handler calling its own server did not return")
+	}
+}
+
+// httpHandle extrai o *HTTPServer guardado em uma instância de http.Server
+func httpHandle(t *testing.T, server object.Object) *HTTPServer {
+	t.Helper()
+	instance, ok := server.(*eval.Instance)
+	if !ok {
+		t.Fatalf("got %s, want an http.Server", server.Inspect())
+	}
+	handle, _ := instance.Get("handle")
+	s, ok := handle.(*HTTPServer)
+	if !ok {
+		t.Fatalf("handle = %v, want an HTTP server", handle)
+	}
+	return s
+}
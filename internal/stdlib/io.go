@@ -0,0 +1,202 @@
+package stdlib
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"jotlango/internal/eval"
+	"jotlango/internal/object"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Funções nativas do módulo io. Falhas do sistema operacional viram
+// *object.Error com o caminho envolvido, nunca pânicos
+func init() {
+	eval.RegisterNatives("io", map[string]interface{}{
+		"ReadFile":   ioReadFile,
+		"WriteFile":  ioWriteFile,
+		"AppendFile": ioAppendFile,
+		"FileExists": ioFileExists,
+		"DeleteFile": ioDeleteFile,
+		"IsDir":      ioIsDir,
+		"ListDir":    ioListDir,
+		"MakeDir":    ioMakeDir,
+		"Glob":       ioGlob,
+		"JoinPath":   filepath.Join,
+		"BaseName":   filepath.Base,
+		"DirName":    filepath.Dir,
+		"Extension":  filepath.Ext,
+		"ReadLines":  ioReadLines,
+		"EachLine":   ioEachLine,
+		"ReadLine":   ioReadLine,
+		"ReadStdin":  ioReadStdin,
+	})
+}
+
+// pathError remove de erros do pacote os a operação interna, mantendo o
+// caminho e a causa: "open x.txt: no such file or directory" vira
+// "x.txt: no such file or directory"
+func pathError(err error) error {
+	var pe *os.PathError
+	if errors.As(err, &pe) {
+		return fmt.Errorf("%s: %v", pe.Path, pe.Err)
+	}
+	return err
+}
+
+func ioReadFile(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", pathError(err)
+	}
+	return string(content), nil
+}
+
+func ioWriteFile(path string, content string) error {
+	return pathError(os.WriteFile(path, []byte(content), 0644))
+}
+
+func ioAppendFile(path string, content string) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return pathError(err)
+	}
+	defer f.Close()
+
+	_, err = f.WriteString(content)
+	return pathError(err)
+}
+
+func ioFileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func ioIsDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+func ioDeleteFile(path string) error {
+	return pathError(os.Remove(path))
+}
+
+// ioListDir retorna os nomes das entradas de um diretório, em ordem
+func ioListDir(path string) ([]string, error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, pathError(err)
+	}
+
+	names := make([]string, len(entries))
+	for i, entry := range entries {
+		names[i] = entry.Name()
+	}
+	return names, nil
+}
+
+func ioMakeDir(path string) error {
+	return pathError(os.MkdirAll(path, 0755))
+}
+
+func ioGlob(pattern string) ([]string, error) {
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %v", pattern, err)
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
+
+func ioReadLines(path string) ([]string, error) {
+	var lines []string
+	err := eachLine(path, func(line string) bool {
+		lines = append(lines, line)
+		return true
+	})
+	return lines, err
+}
+
+// ioEachLine chama fn para cada linha do arquivo sem carregá-lo inteiro na
+// memória. A leitura para quando fn retorna false
+func ioEachLine(path string, fn object.Object) object.Object {
+	var result object.Object = eval.NULL
+
+	err := eachLine(path, func(line string) bool {
+		result = eval.Call(fn, &object.String{Value: line})
+		if _, ok := result.(*object.Error); ok {
+			return false
+		}
+		return result != eval.FALSE
+	})
+
+	if _, ok := result.(*object.Error); ok {
+		return result
+	}
+	if err != nil {
+		return &object.Error{Message: "EachLine: " + err.Error()}
+	}
+	return eval.NULL
+}
+
+func eachLine(path string, fn func(line string) bool) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return pathError(err)
+	}
+	defer f.Close()
+
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" || err == nil {
+			if !fn(strings.TrimRight(line, "\r\n")) {
+				return nil
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return pathError(err)
+		}
+	}
+}
+
+var (
+	stdinMu     sync.Mutex
+	stdinReader = bufio.NewReader(os.Stdin)
+)
+
+// ioReadLine lê uma linha da entrada padrão, sem a quebra de linha.
+// Retorna null no fim da entrada
+func ioReadLine() object.Object {
+	stdinMu.Lock()
+	defer stdinMu.Unlock()
+
+	line, err := stdinReader.ReadString('\n')
+	if err == io.EOF && line == "" {
+		return eval.NULL
+	}
+	if err != nil && err != io.EOF {
+		return &object.Error{Message: fmt.Sprintf("stdin: %v", err)}
+	}
+	return &object.String{Value: strings.TrimRight(line, "\r\n")}
+}
+
+// ioReadStdin lê toda a entrada padrão restante
+func ioReadStdin() (string, error) {
+	stdinMu.Lock()
+	defer stdinMu.Unlock()
+
+	content, err := io.ReadAll(stdinReader)
+	if err != nil {
+		return "", fmt.Errorf("stdin: %v", err)
+	}
+	return string(content), nil
+}
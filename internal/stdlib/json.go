@@ -0,0 +1,278 @@
+package stdlib
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"jotlango/internal/eval"
+	"jotlango/internal/object"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Funções nativas do módulo json
+func init() {
+	eval.RegisterNative("json", "parse", eval.WrapNative("parse", decodeJSON))
+	eval.RegisterNative("json", "stringify", jsonStringify)
+}
+
+// jsonStringify aceita um segundo argumento opcional com a indentação: um
+// número de espaços ou a própria string de indentação
+func jsonStringify(args ...object.Object) object.Object {
+	if len(args) != 1 && len(args) != 2 {
+		return &object.Error{Message: fmt.Sprintf("wrong number of arguments to `stringify`. got=%d, want=1 or 2", len(args))}
+	}
+
+	indent := ""
+	if len(args) == 2 {
+		switch arg := args[1].(type) {
+		case *object.Integer:
+			if arg.Value < 0 || arg.Value > 10 {
+				return &object.Error{Message: fmt.Sprintf("stringify: indent must be between 0 and 10, got %s", arg.Inspect())}
+			}
+			indent = strings.Repeat(" ", int(arg.Value))
+		case *object.String:
+			indent = arg.Value
+		default:
+			return &object.Error{Message: fmt.Sprintf("stringify: indent must be INTEGER or STRING, got %s", arg.Type())}
+		}
+	}
+
+	content, err := encodeJSON(args[0], indent)
+	if err != nil {
+		return &object.Error{Message: "stringify: " + err.Error()}
+	}
+	return &object.String{Value: string(content)}
+}
+
+// encodeJSON serializa um valor JotLang. Hashes mantêm a ordem de
+// inserção; instâncias são serializadas pelas suas propriedades, na ordem
+// de declaração. indent vazio gera a forma compacta
+func encodeJSON(obj object.Object, indent string) ([]byte, error) {
+	e := &jsonEncoder{indent: indent}
+	if err := e.encode(obj, 0); err != nil {
+		return nil, err
+	}
+	return e.buf.Bytes(), nil
+}
+
+type jsonEncoder struct {
+	buf    bytes.Buffer
+	indent string
+	stack  []object.Object // valores sendo serializados, para detectar ciclos
+}
+
+func (e *jsonEncoder) encode(obj object.Object, depth int) error {
+	switch obj := obj.(type) {
+	case *object.Null:
+		e.buf.WriteString("null")
+	case *object.Boolean:
+		e.buf.WriteString(strconv.FormatBool(obj.Value))
+	case *object.Integer:
+		e.buf.WriteString(strconv.FormatInt(obj.Value, 10))
+	case *object.Float:
+		if math.IsNaN(obj.Value) || math.IsInf(obj.Value, 0) {
+			return fmt.Errorf("cannot encode %s as JSON", obj.Inspect())
+		}
+		content, _ := json.Marshal(obj.Value)
+		e.buf.Write(content)
+	case *object.String:
+		e.writeString(obj.Value)
+	case *object.Array:
+		if err := e.enter(obj); err != nil {
+			return err
+		}
+		e.buf.WriteByte('[')
+		elements := obj.Snapshot()
+		for i, element := range elements {
+			if i > 0 {
+				e.buf.WriteByte(',')
+			}
+			e.newline(depth + 1)
+			if err := e.encode(element, depth+1); err != nil {
+				return err
+			}
+		}
+		if len(elements) > 0 {
+			e.newline(depth)
+		}
+		e.buf.WriteByte(']')
+		e.leave()
+	case *object.Hash:
+		if err := e.enter(obj); err != nil {
+			return err
+		}
+		pairs := obj.Ordered()
+		keys := make([]string, len(pairs))
+		values := make([]object.Object, len(pairs))
+		for i, pair := range pairs {
+			keys[i] = stringValue(pair.Key)
+			values[i] = pair.Value
+		}
+		if err := e.encodeObject(keys, values, depth); err != nil {
+			return err
+		}
+		e.leave()
+	case *eval.Instance:
+		if err := e.enter(obj); err != nil {
+			return err
+		}
+		keys := obj.PropertyNames()
+		values := make([]object.Object, len(keys))
+		for i, key := range keys {
+			values[i], _ = obj.Get(key)
+		}
+		if err := e.encodeObject(keys, values, depth); err != nil {
+			return err
+		}
+		e.leave()
+	default:
+		return fmt.Errorf("cannot encode %s as JSON", obj.Type())
+	}
+	return nil
+}
+
+func (e *jsonEncoder) encodeObject(keys []string, values []object.Object, depth int) error {
+	e.buf.WriteByte('{')
+	for i, key := range keys {
+		if i > 0 {
+			e.buf.WriteByte(',')
+		}
+		e.newline(depth + 1)
+		e.writeString(key)
+		e.buf.WriteByte(':')
+		if e.indent != "" {
+			e.buf.WriteByte(' ')
+		}
+		if err := e.encode(values[i], depth+1); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	if len(keys) > 0 {
+		e.newline(depth)
+	}
+	e.buf.WriteByte('}')
+	return nil
+}
+
+func (e *jsonEncoder) enter(obj object.Object) error {
+	for _, seen := range e.stack {
+		if seen == obj {
+			return errors.New("cannot encode cyclic structure as JSON")
+		}
+	}
+	e.stack = append(e.stack, obj)
+	return nil
+}
+
+func (e *jsonEncoder) leave() {
+	e.stack = e.stack[:len(e.stack)-1]
+}
+
+func (e *jsonEncoder) newline(depth int) {
+	if e.indent == "" {
+		return
+	}
+	e.buf.WriteByte('\n')
+	e.buf.WriteString(strings.Repeat(e.indent, depth))
+}
+
+// writeString escreve s entre aspas, sem escapar <, > e &
+func (e *jsonEncoder) writeString(s string) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(s)
+	e.buf.Write(bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+// decodeJSON converte texto JSON em hashes, arrays, números, strings,
+// booleanos e null, mantendo a ordem das chaves dos objetos
+func decodeJSON(text string) (object.Object, error) {
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.UseNumber()
+
+	value, err := decodeJSONValue(decoder)
+	if err != nil {
+		return nil, jsonError(decoder, err)
+	}
+
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("invalid JSON at offset %d: unexpected data after value", decoder.InputOffset())
+	}
+	return value, nil
+}
+
+func decodeJSONValue(decoder *json.Decoder) (object.Object, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token := token.(type) {
+	case nil:
+		return eval.NULL, nil
+	case bool:
+		return eval.FromGo(token), nil
+	case string:
+		return &object.String{Value: token}, nil
+	case json.Number:
+		// Números sem parte decimal nem expoente viram Integer
+		if !strings.ContainsAny(token.String(), ".eE") {
+			if i, err := token.Int64(); err == nil {
+				return &object.Integer{Value: i}, nil
+			}
+		}
+		f, err := token.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("number %s out of range", token)
+		}
+		return &object.Float{Value: f}, nil
+	case json.Delim:
+		switch token {
+		case '[':
+			array := &object.Array{Elements: []object.Object{}}
+			for decoder.More() {
+				element, err := decodeJSONValue(decoder)
+				if err != nil {
+					return nil, err
+				}
+				array.Elements = append(array.Elements, element)
+			}
+			_, err := decoder.Token()
+			return array, err
+		case '{':
+			hash := object.NewHash()
+			for decoder.More() {
+				keyToken, err := decoder.Token()
+				if err != nil {
+					return nil, err
+				}
+				key := &object.String{Value: keyToken.(string)}
+				value, err := decodeJSONValue(decoder)
+				if err != nil {
+					return nil, err
+				}
+				hash.Set(key.HashKey(), object.HashPair{Key: key, Value: value})
+			}
+			_, err := decoder.Token()
+			return hash, err
+		}
+	}
+
+	return nil, fmt.Errorf("unexpected token %v", token)
+}
+
+func jsonError(decoder *json.Decoder, err error) error {
+	var syntax *json.SyntaxError
+	if errors.As(err, &syntax) {
+		return fmt.Errorf("invalid JSON at offset %d: %v", syntax.Offset, syntax)
+	}
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return errors.New("invalid JSON: unexpected end of input")
+	}
+	return fmt.Errorf("invalid JSON at offset %d: %v", decoder.InputOffset(), err)
+}
@@ -0,0 +1,161 @@
+package stdlib
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"jotlango/internal/eval"
+	"jotlango/internal/object"
+)
+
+func TestJSONParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string // Inspect do valor
+		typ   object.ObjectType
+	}{
+		{`null`, "null", object.NULL_OBJ},
+		{`true`, "true", object.BOOLEAN_OBJ},
+		{`42`, "42", object.INTEGER_OBJ},
+		{`-7`, "-7", object.INTEGER_OBJ},
+		{`1.5`, "1.5", object.FLOAT_OBJ},
+		{`1e3`, "1000.0", object.FLOAT_OBJ},
+		{`2.0`, "2.0", object.FLOAT_OBJ},
+		{`9223372036854775808`, "9223372036854776000.0", object.FLOAT_OBJ},
+		{`"a\nb é 😀"`, "a\nb é 😀", object.STRING_OBJ},
+		{`[]`, "[]", object.ARRAY_OBJ},
+		{`[1, "x", [null]]`, "[1, x, [null]]", object.ARRAY_OBJ},
+		// As chaves ficam na ordem do texto
+		{`{"z": 1, "a": 2, "m": {"y": true, "b": false}}`, "{z: 1, a: 2, m: {y: true, b: false}}", object.HASH_OBJ},
+		// Uma chave repetida fica na primeira posição, com o último valor
+		{`{"a": 1, "b": 2, "a": 3}`, "{a: 3, b: 2}", object.HASH_OBJ},
+		{"  \n[1]\n  ", "[1]", object.ARRAY_OBJ},
+	}
+
+	for _, tt := range tests {
+		value, err := decodeJSON(tt.input)
+		if err != nil {
+			t.Errorf("%s: %v", tt.input, err)
+			continue
+		}
+		if value.Type() != tt.typ || value.Inspect() != tt.want {
+			t.Errorf("%s: got %s %s, want %s %s", tt.input, value.Type(), value.Inspect(), tt.typ, tt.want)
+		}
+	}
+}
+
+func TestJSONParseErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{``, "invalid JSON: unexpected end of input"},
+		{`[1, 2`, "unexpected end of JSON input"},
+		{`{"a": }`, "invalid JSON at offset"},
+		{`[1,]`, "invalid JSON at offset"},
+		{`{a: 1}`, "invalid JSON at offset"},
+		{`1 2`, "invalid JSON at offset 3: unexpected data after value"},
+		{`{} {}`, "unexpected data after value"},
+		{`[1] x`, "unexpected data after value"},
+		{`1e999`, "number 1e999 out of range"},
+	}
+
+	for _, tt := range tests {
+		_, err := decodeJSON(tt.input)
+		if err == nil {
+			t.Errorf("%q: expected an error", tt.input)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%q: error = %q, want it to contain %q", tt.input, err, tt.want)
+		}
+	}
+}
+
+func TestJSONStringify(t *testing.T) {
+	hash := eval.FromGo(map[string]interface{}{"a": 1})
+	tests := []struct {
+		args []object.Object
+		want string
+	}{
+		{[]object.Object{eval.NULL}, "null"},
+		{[]object.Object{&object.Float{Value: 0.1}}, "0.1"},
+		{[]object.Object{&object.Float{Value: 2}}, "2"},
+		{[]object.Object{&object.String{Value: "aspas \" e <tag> e \n"}}, `"aspas \" e <tag> e \n"`},
+		{[]object.Object{&object.Array{Elements: []object.Object{}}}, "[]"},
+		{[]object.Object{object.NewHash()}, "{}"},
+		// O mesmo valor pode aparecer mais de uma vez, desde que não dentro
+		// de si mesmo
+		{[]object.Object{&object.Array{Elements: []object.Object{hash, hash}}}, `[{"a":1},{"a":1}]`},
+		{[]object.Object{hash, &object.Integer{Value: 2}}, "{\n  \"a\": 1\n}"},
+		{[]object.Object{hash, &object.String{Value: "\t"}}, "{\n\t\"a\": 1\n}"},
+		{[]object.Object{hash, &object.Integer{Value: 0}}, `{"a":1}`},
+	}
+
+	for _, tt := range tests {
+		result := jsonStringify(tt.args...)
+		if got := result.Inspect(); got != tt.want {
+			t.Errorf("stringify(%s): got %s, want %s", tt.args[0].Inspect(), got, tt.want)
+		}
+	}
+}
+
+func TestJSONStringifyErrors(t *testing.T) {
+	cyclic := object.NewHash()
+	key := &object.String{Value: "self"}
+	cyclic.Set(key.HashKey(), object.HashPair{Key: key, Value: cyclic})
+
+	inner := &object.Array{Elements: []object.Object{}}
+	outer := &object.Array{Elements: []object.Object{inner}}
+	inner.Elements = append(inner.Elements, outer)
+
+	tests := []struct {
+		args []object.Object
+		want string
+	}{
+		{[]object.Object{&object.Float{Value: math.NaN()}}, "stringify: cannot encode NaN as JSON"},
+		{[]object.Object{&object.Float{Value: math.Inf(1)}}, "stringify: cannot encode +Inf as JSON"},
+		{[]object.Object{cyclic}, "stringify: self: cannot encode cyclic structure as JSON"},
+		{[]object.Object{outer}, "stringify: cannot encode cyclic structure as JSON"},
+		{[]object.Object{&object.Builtin{}}, "stringify: cannot encode BUILTIN as JSON"},
+		{[]object.Object{eval.NULL, &object.Integer{Value: 11}}, "stringify: indent must be between 0 and 10, got 11"},
+		{[]object.Object{eval.NULL, eval.TRUE}, "stringify: indent must be INTEGER or STRING, got BOOLEAN"},
+		{nil, "wrong number of arguments to `stringify`. got=0, want=1 or 2"},
+	}
+
+	for _, tt := range tests {
+		errObj, ok := jsonStringify(tt.args...).(*object.Error)
+		if !ok {
+			t.Errorf("%q: expected an error", tt.want)
+			continue
+		}
+		if errObj.Message != tt.want {
+			t.Errorf("message = %q, want %q", errObj.Message, tt.want)
+		}
+	}
+}
+
+// Pela biblioteca json: instâncias são serializadas pelas propriedades e o
+// texto gerado volta ao mesmo valor
+func TestJSONRoundTrip(t *testing.T) {
+	result := run(t, `import "json"
+
+class Usuario {
+    prop nome = ""
+    prop tags = []
+    prop extra
+}
+
+var u = new Usuario()
+u.nome = "Ana"
+u.tags = ["a", "b"]
+var text = json.stringify({"z": u, "a": [1, 2.5, true, null]})
+var back = json.parse(text)
+"${text} ${json.stringify(back) == text} ${back["z"]["nome"]}"`)
+
+	want := `{"z":{"nome":"Ana","tags":["a","b"],"extra":null},"a":[1,2.5,true,null]} true Ana`
+	if got := result.Inspect(); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
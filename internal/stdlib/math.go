@@ -0,0 +1,293 @@
+package stdlib
+
+import (
+	"fmt"
+	"jotlango/internal/eval"
+	"jotlango/internal/object"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Funções nativas do módulo math. Resultados NaN ou infinitos nunca são
+// retornados: viram erros que dizem qual chamada os produziu
+func init() {
+	for name, fn := range map[string]func(float64) float64{
+		"sqrt":  math.Sqrt,
+		"cbrt":  math.Cbrt,
+		"exp":   math.Exp,
+		"log":   math.Log,
+		"log10": math.Log10,
+		"log2":  math.Log2,
+		"sin":   math.Sin,
+		"cos":   math.Cos,
+		"tan":   math.Tan,
+		"asin":  math.Asin,
+		"acos":  math.Acos,
+		"atan":  math.Atan,
+	} {
+		eval.RegisterNative("math", name, finite(name, eval.WrapNative(name, fn)))
+	}
+
+	for name, fn := range map[string]func(float64) float64{
+		"floor": math.Floor,
+		"ceil":  math.Ceil,
+		"round": math.Round,
+		"trunc": math.Trunc,
+	} {
+		eval.RegisterNative("math", name, eval.WrapNative(name, rounding(name, fn)))
+	}
+
+	eval.RegisterNative("math", "pow", finite("pow", eval.WrapNative("pow", math.Pow)))
+	eval.RegisterNative("math", "atan2", finite("atan2", eval.WrapNative("atan2", math.Atan2)))
+	eval.RegisterNative("math", "hypot", finite("hypot", eval.WrapNative("hypot", math.Hypot)))
+	eval.RegisterNative("math", "abs", mathAbs)
+	eval.RegisterNative("math", "min", mathMin)
+	eval.RegisterNative("math", "max", mathMax)
+	eval.RegisterNative("math", "clamp", mathClamp)
+	eval.RegisterNative("math", "div", eval.WrapNative("div", mathDiv))
+	eval.RegisterNative("math", "mod", eval.WrapNative("mod", mathMod))
+	eval.RegisterNative("math", "random", eval.WrapNative("random", mathRandom))
+	eval.RegisterNative("math", "randomInt", eval.WrapNative("randomInt", mathRandomInt))
+	eval.RegisterNative("math", "seed", eval.WrapNative("seed", mathSeed))
+}
+
+// finite transforma resultados NaN e infinitos de fn em erros
+func finite(name string, fn object.BuiltinFunction) object.BuiltinFunction {
+	return func(args ...object.Object) object.Object {
+		result := fn(args...)
+
+		n, ok := result.(*object.Float)
+		if !ok {
+			return result
+		}
+
+		switch {
+		case math.IsNaN(n.Value):
+			return &object.Error{Message: fmt.Sprintf("math.%s(%s) is undefined (NaN)", name, inspectArgs(args))}
+		case math.IsInf(n.Value, 0):
+			return &object.Error{Message: fmt.Sprintf("math.%s(%s) is not finite (%s)", name, inspectArgs(args), formatInf(n.Value))}
+		}
+
+		return result
+	}
+}
+
+func inspectArgs(args []object.Object) string {
+	out := ""
+	for i, arg := range args {
+		if i > 0 {
+			out += ", "
+		}
+		out += arg.Inspect()
+	}
+	return out
+}
+
+func formatInf(f float64) string {
+	if f < 0 {
+		return "-Infinity"
+	}
+	return "+Infinity"
+}
+
+// rounding adapta uma função de arredondamento para retornar Integer
+func rounding(name string, fn func(float64) float64) func(float64) (int64, error) {
+	return func(x float64) (int64, error) {
+		r := fn(x)
+		if r < math.MinInt64 || r >= math.MaxInt64 {
+			return 0, fmt.Errorf("%s does not fit in an integer", object.FormatFloat(r))
+		}
+		return int64(r), nil
+	}
+}
+
+// number converte um argumento Integer ou Float para float64
+func number(name string, i int, arg object.Object) (float64, *object.Error) {
+	switch arg := arg.(type) {
+	case *object.Integer:
+		return float64(arg.Value), nil
+	case *object.Float:
+		return arg.Value, nil
+	}
+	return 0, &object.Error{Message: fmt.Sprintf("math.%s: argument %d must be INTEGER or FLOAT, got %s", name, i+1, arg.Type())}
+}
+
+// mathAbs preserva o tipo do argumento
+func mathAbs(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return &object.Error{Message: fmt.Sprintf("wrong number of arguments to `abs`. got=%d, want=1", len(args))}
+	}
+
+	switch arg := args[0].(type) {
+	case *object.Integer:
+		if arg.Value == math.MinInt64 {
+			return &object.Error{Message: fmt.Sprintf("math.abs(%d) overflows an integer", arg.Value)}
+		}
+		if arg.Value < 0 {
+			return &object.Integer{Value: -arg.Value}
+		}
+		return arg
+	case *object.Float:
+		return &object.Float{Value: math.Abs(arg.Value)}
+	}
+	_, err := number("abs", 0, args[0])
+	return err
+}
+
+// numbers aceita números soltos ou um único array de números
+func numbers(name string, args []object.Object) ([]object.Object, []float64, *object.Error) {
+	if len(args) == 1 {
+		if arr, ok := args[0].(*object.Array); ok {
+			args = arr.Snapshot()
+		}
+	}
+
+	if len(args) == 0 {
+		return nil, nil, &object.Error{Message: fmt.Sprintf("math.%s needs at least one number", name)}
+	}
+
+	values := make([]float64, len(args))
+	for i, arg := range args {
+		value, err := number(name, i, arg)
+		if err != nil {
+			return nil, nil, err
+		}
+		values[i] = value
+	}
+
+	return args, values, nil
+}
+
+// mathMin e mathMax retornam o próprio argumento escolhido, então o tipo
+// (Integer ou Float) é preservado
+func mathMin(args ...object.Object) object.Object {
+	args, values, err := numbers("min", args)
+	if err != nil {
+		return err
+	}
+
+	min := 0
+	for i, v := range values {
+		if v < values[min] {
+			min = i
+		}
+	}
+	return args[min]
+}
+
+func mathMax(args ...object.Object) object.Object {
+	args, values, err := numbers("max", args)
+	if err != nil {
+		return err
+	}
+
+	max := 0
+	for i, v := range values {
+		if v > values[max] {
+			max = i
+		}
+	}
+	return args[max]
+}
+
+func mathClamp(args ...object.Object) object.Object {
+	if len(args) != 3 {
+		return &object.Error{Message: fmt.Sprintf("wrong number of arguments to `clamp`. got=%d, want=3", len(args))}
+	}
+
+	values := make([]float64, 3)
+	for i, arg := range args {
+		value, err := number("clamp", i, arg)
+		if err != nil {
+			return err
+		}
+		values[i] = value
+	}
+
+	x, lo, hi := values[0], values[1], values[2]
+	switch {
+	case lo > hi:
+		return &object.Error{Message: fmt.Sprintf("math.clamp: lower bound %s is greater than upper bound %s", args[1].Inspect(), args[2].Inspect())}
+	case x < lo:
+		return args[1]
+	case x > hi:
+		return args[2]
+	}
+	return args[0]
+}
+
+// mathDiv é a divisão inteira arredondada para baixo
+func mathDiv(a, b int64) (int64, error) {
+	if b == 0 {
+		return 0, fmt.Errorf("integer division by zero")
+	}
+	if a == math.MinInt64 && b == -1 {
+		return 0, fmt.Errorf("integer overflow: div(%d, %d)", a, b)
+	}
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q, nil
+}
+
+// mathMod é o resto da divisão inteira, com o sinal do divisor
+func mathMod(a, b int64) (int64, error) {
+	if b == 0 {
+		return 0, fmt.Errorf("integer modulo by zero")
+	}
+	r := a % b
+	if r != 0 && ((r < 0) != (b < 0)) {
+		r += b
+	}
+	return r, nil
+}
+
+var (
+	randMu sync.Mutex
+	rng    = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+// mathRandom retorna um número no intervalo [0, 1)
+func mathRandom() float64 {
+	randMu.Lock()
+	defer randMu.Unlock()
+	return rng.Float64()
+}
+
+// mathRandomInt retorna um inteiro no intervalo [min, max]
+func mathRandomInt(min, max int64) (int64, error) {
+	if min > max {
+		return 0, fmt.Errorf("min %d is greater than max %d", min, max)
+	}
+	randMu.Lock()
+	defer randMu.Unlock()
+
+	// A diferença em uint64 não estoura mesmo com min e max nos extremos
+	span := uint64(max) - uint64(min)
+	if span < math.MaxInt64 {
+		return min + rng.Int63n(int64(span)+1), nil
+	}
+	if span == math.MaxUint64 {
+		return int64(rng.Uint64()), nil
+	}
+
+	// Intervalos maiores que int64: descarta os valores abaixo de
+	// threshold para que todos os restos sejam igualmente prováveis
+	n := span + 1
+	threshold := -n % n
+	for {
+		if v := rng.Uint64(); v >= threshold {
+			return int64(uint64(min) + v%n), nil
+		}
+	}
+}
+
+// mathSeed reinicia o gerador para produzir uma sequência reproduzível
+func mathSeed(seed int64) {
+	randMu.Lock()
+	defer randMu.Unlock()
+	rng = rand.New(rand.NewSource(seed))
+}
@@ -0,0 +1,96 @@
+package stdlib
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMathRandomIntRanges(t *testing.T) {
+	mathSeed(1)
+	tests := []struct {
+		min, max int64
+	}{
+		{0, 0},
+		{-3, 3},
+		{0, math.MaxInt64},
+		{-1, math.MaxInt64},
+		{math.MinInt64, 0},
+		{math.MinInt64, -1},
+		{math.MinInt64, math.MaxInt64},
+		{math.MinInt64, math.MinInt64},
+		{math.MaxInt64, math.MaxInt64},
+		{math.MinInt64 / 2, math.MaxInt64/2 + 2},
+	}
+
+	for _, tt := range tests {
+		for i := 0; i < 100; i++ {
+			n, err := mathRandomInt(tt.min, tt.max)
+			if err != nil {
+				t.Fatalf("randomInt(%d, %d): %v", tt.min, tt.max, err)
+			}
+			if n < tt.min || n > tt.max {
+				t.Fatalf("randomInt(%d, %d) = %d, out of range", tt.min, tt.max, n)
+			}
+		}
+	}
+
+	if _, err := mathRandomInt(1, 0); err == nil {
+		t.Error("randomInt(1, 0): expected an error")
+	}
+}
+
+func TestMathRandomIntSeed(t *testing.T) {
+	sequence := func() []int64 {
+		mathSeed(42)
+		var values []int64
+		for i := 0; i < 5; i++ {
+			n, _ := mathRandomInt(math.MinInt64, math.MaxInt64)
+			values = append(values, n)
+		}
+		return values
+	}
+
+	first, second := sequence(), sequence()
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("same seed gave %v and %v", first, second)
+		}
+	}
+}
+
+func TestMathDivMod(t *testing.T) {
+	tests := []struct {
+		a, b     int64
+		div, mod int64
+	}{
+		{7, 2, 3, 1},
+		{-7, 2, -4, 1},
+		{-7, 3, -3, 2},
+		{7, -3, -3, -2},
+		{math.MinInt64, 1, math.MinInt64, 0},
+		{math.MaxInt64, -1, -math.MaxInt64, 0},
+	}
+
+	for _, tt := range tests {
+		if q, err := mathDiv(tt.a, tt.b); err != nil || q != tt.div {
+			t.Errorf("div(%d, %d) = %d, %v, want %d", tt.a, tt.b, q, err, tt.div)
+		}
+		if r, err := mathMod(tt.a, tt.b); err != nil || r != tt.mod {
+			t.Errorf("mod(%d, %d) = %d, %v, want %d", tt.a, tt.b, r, err, tt.mod)
+		}
+	}
+
+	// Como em /, o único quociente que não cabe em int é um erro
+	if _, err := mathDiv(math.MinInt64, -1); err == nil || err.Error() != "integer overflow: div(-9223372036854775808, -1)" {
+		t.Errorf("div(MinInt64, -1): err = %v, want an overflow error", err)
+	}
+	if r, err := mathMod(math.MinInt64, -1); err != nil || r != 0 {
+		t.Errorf("mod(MinInt64, -1) = %d, %v, want 0", r, err)
+	}
+	if _, err := mathDiv(1, 0); err == nil {
+		t.Error("div(1, 0): expected an error")
+	}
+	if _, err := mathMod(1, 0); err == nil {
+		t.Error("mod(1, 0): expected an error")
+	}
+}
@@ -0,0 +1,66 @@
+package stdlib
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"jotlango/internal/eval"
+	"jotlango/internal/lexer"
+	"jotlango/internal/object"
+	"jotlango/internal/parser"
+)
+
+// run avalia src como o arquivo principal main.jt, com a biblioteca padrão
+// do repositório
+func run(t *testing.T, src string) object.Object {
+	t.Helper()
+	return runFile(t, filepath.Join(t.TempDir(), "main.jt"), src)
+}
+
+// runFile avalia src como o arquivo file, que não precisa existir; um
+// jot.json no mesmo diretório é usado como o projeto
+func runFile(t *testing.T, file string, src string) object.Object {
+	t.Helper()
+	eval.SetStdlib(os.DirFS("../../stdlib"))
+
+	p := parser.NewParser(lexer.NewFileLexer(file, src))
+	program := p.ParseProgram()
+	for _, d := range p.Diagnostics() {
+		t.Fatalf("%q: unexpected diagnostic: %s", src, d)
+	}
+
+	evaluator, err := eval.NewFileEvaluator(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return evaluator.Eval(program)
+}
+
+// Erros levantados dentro da biblioteca padrão apontam para a chamada no
+// código do usuário, com a posição original em uma nota
+func TestStdlibErrorPosition(t *testing.T) {
+	result := run(t, "import \"math\"\n\nfn half(x) {\n    return math.sqrt(x) / 2\n}\nvar y = half(-1)")
+	errObj, ok := result.(*object.Error)
+	if !ok {
+		t.Fatalf("got %s, want an error", result.Inspect())
+	}
+
+	d := eval.Diagnostic(errObj)
+	if start := d.Span.Start; filepath.Base(start.File) != "main.jt" || start.Line != 4 {
+		t.Errorf("position = %s, want main.jt line 4", start)
+	}
+	if len(d.Related) != 1 || d.Related[0].Span.Start.File != "stdlib:math/math.jt" {
+		t.Errorf("related = %v, want a note in stdlib:math/math.jt", d.Related)
+	}
+
+	// Um erro no callback do usuário mantém a posição do callback
+	result = run(t, "import \"io\"\nio.EachLine(\"stdlib_test.go\", fn(line) {\n    1 / 0\n})")
+	errObj, ok = result.(*object.Error)
+	if !ok {
+		t.Fatalf("got %s, want an error", result.Inspect())
+	}
+	if errObj.Pos.Line != 3 || errObj.Origin.IsValid() {
+		t.Errorf("callback error at %s (origin %s), want line 3 and no origin", errObj.Pos, errObj.Origin)
+	}
+}
@@ -1,49 +1,93 @@
 package main
 
 import (
+	"embed"
+	"flag"
 	"fmt"
+	"io/fs"
+	"jotlango/internal/diag"
 	"jotlango/internal/eval"
 	"jotlango/internal/lexer"
+	"jotlango/internal/object"
 	"jotlango/internal/parser"
 	"os"
+
+	_ "jotlango/internal/stdlib" // registra as funções nativas da biblioteca padrão
 )
 
+// A biblioteca padrão vai embutida no executável, para que import "math"
+// funcione em qualquer projeto
+//
+//go:embed stdlib
+var stdlibFiles embed.FS
+
 func main() {
+	stdlib, _ := fs.Sub(stdlibFiles, "stdlib")
+	eval.SetStdlib(stdlib)
+
 	if len(os.Args) < 3 {
-		fmt.Println("Uso: jot run <arquivo>")
+		fmt.Println("Uso: jot run [--diagnostics=text|json] [--strict-index] <arquivo>")
 		os.Exit(1)
 	}
 
 	command := os.Args[1]
-	file := os.Args[2]
 
 	if command != "run" {
 		fmt.Println("Comando desconhecido:", command)
 		os.Exit(1)
 	}
 
+	flags := flag.NewFlagSet("run", flag.ExitOnError)
+	format := flags.String("diagnostics", "text", "formato dos diagnósticos: text ou json")
+	strictIndex := flags.Bool("strict-index", false, "índices fora dos limites de um array resultam em erro")
+	flags.Parse(os.Args[2:])
+
+	if flags.NArg() < 1 || (*format != "text" && *format != "json") {
+		fmt.Println("Uso: jot run [--diagnostics=text|json] [--strict-index] <arquivo>")
+		os.Exit(1)
+	}
+
+	file := flags.Arg(0)
+	eval.SetStrictIndex(*strictIndex)
+
 	content, err := os.ReadFile(file)
 	if err != nil {
 		fmt.Println("Erro ao ler arquivo:", err)
 		os.Exit(1)
 	}
 
-	l := lexer.NewLexer(string(content))
+	l := lexer.NewFileLexer(file, string(content))
 	p := parser.NewParser(l)
 	program := p.ParseProgram()
 
-	if len(p.Errors()) > 0 {
-		fmt.Println("Erros de parsing:")
-		for _, err := range p.Errors() {
-			fmt.Println(err)
+	if diag.HasErrors(p.Diagnostics()) {
+		if *format == "json" {
+			diag.WriteJSON(os.Stdout, p.Diagnostics())
+		} else {
+			diag.WriteText(os.Stderr, p.Diagnostics())
 		}
 		os.Exit(1)
 	}
 
-	evaluator := eval.NewEvaluator()
+	evaluator, err := eval.NewFileEvaluator(file)
+	if err != nil {
+		fmt.Println("Erro ao carregar projeto:", err)
+		os.Exit(1)
+	}
+
 	result := evaluator.Eval(program)
 
-	if result != nil {
+	if errObj, ok := result.(*object.Error); ok {
+		diagnostics := []diag.Diagnostic{eval.Diagnostic(errObj)}
+		if *format == "json" {
+			diag.WriteJSON(os.Stdout, diagnostics)
+		} else {
+			diag.WriteText(os.Stderr, diagnostics)
+		}
+		os.Exit(1)
+	}
+
+	if result != nil && result != eval.NULL {
 		fmt.Println(result.Inspect())
 	}
 }